@@ -0,0 +1,48 @@
+package clock
+
+import "time"
+
+// Clock is the interface that wraps time access,
+// it allows tests to control time deterministically.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// NewTimer creates a new Timer firing after d.
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer is the interface of a single event timer, see time.Timer.
+type Timer interface {
+	// C returns the channel the time is delivered on.
+	C() <-chan time.Time
+	// Stop prevents the Timer from firing.
+	Stop() bool
+	// Reset changes the timer to expire after d.
+	Reset(d time.Duration) bool
+}
+
+// New returns the real clock.
+func New() Clock {
+	return realClock{}
+}
+
+type realClock struct{}
+
+// Now returns the current time.
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+// NewTimer creates a new Timer firing after d.
+func (realClock) NewTimer(d time.Duration) Timer {
+	return realTimer{time.NewTimer(d)}
+}
+
+type realTimer struct {
+	*time.Timer
+}
+
+// C returns the channel the time is delivered on.
+func (t realTimer) C() <-chan time.Time {
+	return t.Timer.C
+}
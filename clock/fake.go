@@ -0,0 +1,149 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Fake is a Clock which time moves only by Advance.
+type Fake struct {
+	mu   sync.Mutex
+	cond *sync.Cond
+	now  time.Time
+	// timers are the active ones, fired and stopped timers are removed.
+	timers []*fakeTimer
+}
+
+// NewFake returns a Fake clock set to now.
+func NewFake(now time.Time) *Fake {
+	f := &Fake{now: now}
+	f.cond = sync.NewCond(&f.mu)
+
+	return f
+}
+
+// Now returns the current fake time.
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.now
+}
+
+// NewTimer creates a new Timer firing once the clock is advanced past d.
+func (f *Fake) NewTimer(d time.Duration) Timer {
+	t := &fakeTimer{
+		fake: f,
+		c:    make(chan time.Time, 1),
+	}
+
+	f.mu.Lock()
+	t.reset(d)
+	f.mu.Unlock()
+
+	return t
+}
+
+// Advance moves the clock forward and fires expired timers.
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.now = f.now.Add(d)
+	// fire removes the timer from f.timers, iterate a copy.
+	for _, t := range append([]*fakeTimer(nil), f.timers...) {
+		if !t.deadline.After(f.now) {
+			t.fire()
+		}
+	}
+	f.cond.Broadcast()
+}
+
+// BlockUntil waits until n timers are waiting to fire,
+// so a test knows the code under test is ready to be advanced.
+func (f *Fake) BlockUntil(n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for f.waiters() < n {
+		f.cond.Wait()
+	}
+}
+
+func (f *Fake) waiters() int {
+	return len(f.timers)
+}
+
+// remove drops the timer from the active ones, the caller holds the lock.
+func (f *Fake) remove(t *fakeTimer) {
+	for i := range f.timers {
+		if f.timers[i] == t {
+			f.timers = append(f.timers[:i], f.timers[i+1:]...)
+			return
+		}
+	}
+}
+
+type fakeTimer struct {
+	fake     *Fake
+	c        chan time.Time
+	deadline time.Time
+	active   bool
+}
+
+// C returns the channel the time is delivered on.
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.c
+}
+
+// Stop prevents the Timer from firing.
+func (t *fakeTimer) Stop() bool {
+	t.fake.mu.Lock()
+	defer t.fake.mu.Unlock()
+
+	active := t.active
+	t.active = false
+	t.fake.remove(t)
+	t.fake.cond.Broadcast()
+
+	return active
+}
+
+// Reset changes the timer to expire after d.
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	t.fake.mu.Lock()
+	defer t.fake.mu.Unlock()
+
+	active := t.active
+	t.reset(d)
+
+	return active
+}
+
+// reset arms the timer, the caller holds the clock lock.
+func (t *fakeTimer) reset(d time.Duration) {
+	// Drop a stale value, a reset timer only delivers its new expiry.
+	select {
+	case <-t.c:
+	default:
+	}
+
+	t.deadline = t.fake.now.Add(d)
+	if !t.active {
+		t.fake.timers = append(t.fake.timers, t)
+	}
+	t.active = true
+	if d <= 0 {
+		t.fire()
+	}
+	t.fake.cond.Broadcast()
+}
+
+func (t *fakeTimer) fire() {
+	t.active = false
+	t.fake.remove(t)
+	select {
+	case t.c <- t.fake.now:
+	default:
+	}
+}
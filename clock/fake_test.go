@@ -0,0 +1,74 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFake_Timer(t *testing.T) {
+	start := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	f := NewFake(start)
+	timer := f.NewTimer(time.Minute)
+
+	f.Advance(59 * time.Second)
+	select {
+	case <-timer.C():
+		t.Fatal("timer fired before its deadline")
+	default:
+	}
+
+	f.Advance(time.Second)
+	select {
+	case got := <-timer.C():
+		if want := start.Add(time.Minute); !got.Equal(want) {
+			t.Errorf("timer fired at %v, want %v", got, want)
+		}
+	default:
+		t.Fatal("timer did not fire at its deadline")
+	}
+
+	if timer.Reset(time.Second) {
+		t.Error("Reset() of a fired timer returns true")
+	}
+	if !timer.Stop() {
+		t.Error("Stop() of an active timer returns false")
+	}
+	f.Advance(time.Hour)
+	select {
+	case <-timer.C():
+		t.Fatal("stopped timer fired")
+	default:
+	}
+}
+
+func TestFake_BlockUntil(t *testing.T) {
+	f := NewFake(time.Time{})
+	done := make(chan struct{})
+	go func() {
+		f.BlockUntil(1)
+		close(done)
+	}()
+
+	f.NewTimer(time.Second)
+	<-done
+}
+
+func TestFake_TimersRemoved(t *testing.T) {
+	f := NewFake(time.Time{})
+	for i := 0; i < 10; i++ {
+		f.NewTimer(time.Second)
+		f.NewTimer(time.Hour).Stop()
+	}
+	f.Advance(time.Second)
+
+	if n := len(f.timers); n != 0 {
+		t.Errorf("%d timers kept after firing and stopping", n)
+	}
+
+	timer := f.NewTimer(time.Second)
+	timer.Stop()
+	timer.Reset(time.Second)
+	if n := len(f.timers); n != 1 {
+		t.Errorf("%d timers kept, want the reset one", n)
+	}
+}
@@ -3,78 +3,208 @@ package main
 import (
 	"context"
 	"errors"
-	"flag"
+	"fmt"
+	stdlog "log"
 	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"sync"
 	"syscall"
-	"time"
+	// Embedded zone database for cron time zones on hosts without tzdata.
+	_ "time/tzdata"
 
+	"github.com/dolefir/refresh-hash/clock"
 	"github.com/dolefir/refresh-hash/config"
 	gen "github.com/dolefir/refresh-hash/gen/proto"
+	"github.com/dolefir/refresh-hash/generator"
+	"github.com/dolefir/refresh-hash/health"
 	"github.com/dolefir/refresh-hash/logger"
+	"github.com/dolefir/refresh-hash/metrics"
+	"github.com/dolefir/refresh-hash/models"
+	"github.com/dolefir/refresh-hash/repository"
+	fileRepository "github.com/dolefir/refresh-hash/repository/file"
 	inmemRepository "github.com/dolefir/refresh-hash/repository/inmem"
+	"github.com/dolefir/refresh-hash/server/accesslog"
 	"github.com/dolefir/refresh-hash/server/grpc/handler"
+	"github.com/dolefir/refresh-hash/server/grpc/interceptors"
 	"github.com/dolefir/refresh-hash/server/restapi"
 	hashesHandler "github.com/dolefir/refresh-hash/server/restapi/handlers"
+	"github.com/dolefir/refresh-hash/services"
 	hashService "github.com/dolefir/refresh-hash/services/hashes"
 	"github.com/dolefir/refresh-hash/task"
+	"github.com/dolefir/refresh-hash/tracing"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
+	grpcHealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const (
+	repositoryInmem = "inmem"
+	repositoryFile  = "file"
 )
 
 func main() {
-	cfgPath := flag.String("config", "config.yaml", "Configuration file")
-	flag.Parse()
-	cfg := config.NewConfig(*cfgPath)
+	loader, err := config.NewLoader(os.Args[1:])
+	if err != nil {
+		stdlog.Fatal(err)
+	}
+	cfg, err := loader.Load()
+	if err != nil {
+		stdlog.Fatalf("invalid config:\n%v", err)
+	}
+
+	log, err := logger.OpenLogger((*logger.CFGLogger)(&cfg.Logger), nil)
+	if err != nil {
+		stdlog.Fatal(err)
+	}
+	levels := logger.NewLevelControl(log)
+	stopTracing, err := tracing.Setup(context.Background(), (*tracing.CFGTracing)(&cfg.Tracing))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	hashRepo, err := newHashRepository(cfg.Repository)
+	if err != nil {
+		log.Fatal(err)
+	}
+	hashGen, err := generator.New((*generator.CFGGenerator)(&cfg.Generator))
+	if err != nil {
+		log.Fatal(err)
+	}
 
-	log := logger.NewLogger((*logger.CFGLogger)(&cfg.Logger), nil)
+	names := make([]string, 0, len(cfg.Namespaces))
+	for _, ns := range cfg.Namespaces {
+		names = append(names, ns.Name)
+	}
 
-	hashRepo := inmemRepository.NewRepository()
-	hashSrv := hashService.NewService(hashRepo, log)
+	clk := clock.New()
+	metric := metrics.New()
+	tickers := task.Tickers{}
+	hashSrv := hashService.NewService(
+		hashRepo,
+		log,
+		hashService.WithClock(clk),
+		hashService.WithScheduler(tickers),
+		hashService.WithRecorder(metric),
+		hashService.WithGracePeriod(cfg.Ticker.GracePeriod),
+		hashService.WithGenerator(hashGen),
+		hashService.WithNamespaces(names...),
+	)
 	hashHdl := hashesHandler.NewHandler(hashSrv)
 
-	ticker := task.NewRefreshTicker(cfg.Ticker.Timer, cfg.Ticker.Timeout, hashSrv, log)
+	// Every namespace is rotated by its own ticker.
+	for _, ns := range namespaces(cfg) {
+		ticker, err := newTicker(ns, cfg.Ticker, hashSrv, clk, log)
+		if err != nil {
+			log.Fatal(err)
+		}
+		tickers[ns.Name] = ticker
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	var checkerOptions []health.Option
+	if pinger, ok := hashRepo.(repository.Pinger); ok {
+		checkerOptions = append(checkerOptions, health.WithRepository(pinger))
+	}
+	checker := health.NewChecker(tickers, cfg.Health.StaleMultiplier, clk, log, checkerOptions...)
+
 	// gRPC setup.
 	list, err := net.Listen(cfg.APIServer.HTTP.Network, cfg.APIServer.HTTP.AddrGrpc)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	grpcHandler := handler.NewHashService(hashSrv)
-	serviceRegistrar := grpc.NewServer()
+	unaryInterceptors := []grpc.UnaryServerInterceptor{interceptors.UnaryTraceParent(), interceptors.UnaryRequestID(log)}
+	streamInterceptors := []grpc.StreamServerInterceptor{interceptors.StreamTraceParent(), interceptors.StreamRequestID(log)}
+	var accessLog *accesslog.Policy
+	if cfg.AccessLog.Enabled {
+		accessLog = accesslog.NewPolicy(cfg.AccessLog)
+		unaryInterceptors = append(unaryInterceptors, interceptors.UnaryAccessLog(log, accessLog))
+		streamInterceptors = append(streamInterceptors, interceptors.StreamAccessLog(log, accessLog))
+	}
+
+	serverOptions := []grpc.ServerOption{
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(append(unaryInterceptors, interceptors.UnaryMetrics(metric), interceptors.UnaryRecovery(log))...),
+		grpc.ChainStreamInterceptor(append(streamInterceptors, interceptors.StreamMetrics(metric), interceptors.StreamRecovery(log))...),
+	}
+
+	grpcHandler := handler.NewHashService(hashSrv, handler.ReadOnly())
+	serviceRegistrar := grpc.NewServer(serverOptions...)
 	gen.RegisterHashServiceServer(serviceRegistrar, grpcHandler)
+	healthSrv := grpcHealth.NewServer()
+	healthpb.RegisterHealthServer(serviceRegistrar, healthSrv)
 	go func() {
 		if err := serviceRegistrar.Serve(list); err != nil {
 			log.Fatal(err)
 		}
 	}()
 
+	// Admin gRPC server, served only on its own address.
+	var adminRegistrar *grpc.Server
+	adminHandler := handler.NewHashService(hashSrv)
+	if cfg.APIServer.HTTP.AdminAddrGrpc != "" {
+		adminList, err := net.Listen(cfg.APIServer.HTTP.Network, cfg.APIServer.HTTP.AdminAddrGrpc)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		adminRegistrar = grpc.NewServer(serverOptions...)
+		gen.RegisterAdminServiceServer(adminRegistrar, handler.NewAdminService(levels))
+		gen.RegisterHashServiceServer(adminRegistrar, adminHandler)
+		go func() {
+			if err := adminRegistrar.Serve(adminList); err != nil {
+				log.Fatal(err)
+			}
+		}()
+	}
+
 	// REST API setup.
-	api := restapi.NewAPI(hashHdl, cfg.APIServer, log)
+	api := restapi.NewAPI(
+		hashHdl,
+		cfg.APIServer,
+		log,
+		restapi.WithMetrics(metric),
+		restapi.WithHealth(hashesHandler.NewHealth(checker)),
+		restapi.WithLogLevel(hashesHandler.NewLogLevel(levels)),
+		restapi.WithAccessLog(accessLog),
+		restapi.WithTracing(cfg.Tracing.ServiceName),
+	)
 
 	go func() {
 		if err := api.ListenAndServe(ctx); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			log.Error(err)
 		}
 	}()
-
-	// Ticker setup.
-	var wg sync.WaitGroup
-	wg.Add(1)
 	go func() {
-		if err := ticker.Start(ctx); err != nil {
+		if err := api.ListenAndServeAdmin(ctx); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			log.Error(err)
 		}
-		wg.Done()
 	}()
 
-	// Metrics can also be configured
-	// in this selection...
+	// Ticker setup.
+	var wg sync.WaitGroup
+	for _, ticker := range tickers {
+		wg.Add(1)
+		go func(ticker task.RefreshTicker) {
+			if err := ticker.Start(ctx); err != nil {
+				log.Error(err)
+			}
+			wg.Done()
+		}(ticker)
+	}
+
+	go checker.Watch(ctx, healthSrv, cfg.Health.CheckInterval)
+
+	// Config reload setup.
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	reload := &reloader{loader: loader, cfg: cfg, tickers: tickers, levels: levels, log: log}
+	go reload.run(ctx, hup)
 
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
@@ -82,18 +212,120 @@ func main() {
 	<-quit
 
 	log.Info("shutdown...")
-	cancel()
+	// Not ready before anything stops, balancers stop routing
+	// new requests while in-flight ones drain.
+	checker.Shutdown()
+	healthSrv.Shutdown()
+	tickersDone := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(tickersDone)
+	}()
 
-	shutdownCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
-	defer cancel()
+	err = shutdown(cfg.APIServer.HTTP.GracefulTimeout, log,
+		component{
+			name: "ticker",
+			stop: func(ctx context.Context) error {
+				cancel()
+				return waitDone(ctx, tickersDone)
+			},
+		},
+		component{
+			name: "REST API",
+			stop: func(ctx context.Context) error {
+				if err := api.Shutdown(ctx); err != nil {
+					_ = api.Close()
+					return err
+				}
+				return nil
+			},
+		},
+		component{
+			name: "gRPC server",
+			stop: func(ctx context.Context) error {
+				grpcHandler.Close()
+				adminHandler.Close()
 
-	if err := api.Shutdown(shutdownCtx); err != nil {
-		log.Fatal("server forced to shutdown: ", err)
+				stopped := make(chan struct{})
+				go func() {
+					serviceRegistrar.GracefulStop()
+					if adminRegistrar != nil {
+						adminRegistrar.GracefulStop()
+					}
+					close(stopped)
+				}()
+				if err := waitDone(ctx, stopped); err != nil {
+					serviceRegistrar.Stop()
+					if adminRegistrar != nil {
+						adminRegistrar.Stop()
+					}
+					return err
+				}
+				return nil
+			},
+		},
+		component{
+			name: "tracing",
+			stop: stopTracing,
+		},
+	)
+	if err != nil {
+		log.Error("forced to shutdown")
+		_ = log.Close()
+		os.Exit(1)
 	}
 
-	serviceRegistrar.GracefulStop()
+	log.Info("successfully stopped")
+	_ = log.Close()
+}
 
-	wg.Wait()
+// newHashRepository returns the hash storage selected in the config.
+func newHashRepository(cfg config.Repository) (repository.Inmem, error) {
+	retention := repository.Retention{
+		MaxCount: cfg.HistorySize,
+		MaxAge:   cfg.HistoryMaxAge,
+	}
 
-	log.Info("successfully stopped")
+	switch cfg.Type {
+	case repositoryFile:
+		return fileRepository.NewRepository(cfg.Path, retention)
+	case repositoryInmem, "":
+		return inmemRepository.NewRepository(retention), nil
+	default:
+		return nil, fmt.Errorf("unknown repository type %q", cfg.Type)
+	}
+}
+
+// namespaces returns the default namespace followed by the configured ones.
+func namespaces(cfg *config.Main) []config.Namespace {
+	return append([]config.Namespace{{Name: models.DefaultNamespace}}, cfg.Namespaces...)
+}
+
+// newTicker returns a ticker rotating the namespace hash.
+func newTicker(
+	ns config.Namespace,
+	cfg config.Ticker,
+	hashSrv services.Hash,
+	clk clock.Clock,
+	log logger.Logger,
+) (task.RefreshTicker, error) {
+	schedule, err := namespaceSchedule(ns, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	refresher := task.RefresherFunc(func(ctx context.Context) error {
+		_, err := hashSrv.Refresh(ctx, ns.Name)
+		return err
+	})
+
+	return task.NewRefreshTicker(
+		ns.Timer,
+		cfg.Timeout,
+		refresher,
+		log.With("namespace", ns.Name),
+		task.WithRetry(task.RetryPolicy(cfg.Retry)),
+		task.WithSchedule(schedule),
+		task.WithClock(clk),
+	), nil
 }
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/dolefir/refresh-hash/config"
+	"github.com/dolefir/refresh-hash/logger"
+	"github.com/dolefir/refresh-hash/task"
+)
+
+// reloadDebounce coalesces bursts of writes to the config file.
+const reloadDebounce = 500 * time.Millisecond
+
+// reloadable keys are applied live, a change of any other key needs a restart.
+var reloadable = []string{
+	"ticker.timer",
+	"ticker.cron",
+	"ticker.time-zone",
+	"namespaces",
+	"logger.log-level",
+}
+
+// reloader applies reloadable settings of a changed config.
+type reloader struct {
+	loader  *config.Loader
+	cfg     *config.Main
+	tickers task.Tickers
+	levels  *logger.LevelControl
+	log     logger.Logger
+}
+
+// run reloads the config on every trigger until ctx is done.
+func (r *reloader) run(ctx context.Context, signals <-chan os.Signal) {
+	changes, err := r.loader.Watch(ctx, reloadDebounce)
+	if err != nil {
+		r.log.Warnf("config file is not watched, reload on SIGHUP only: %v", err)
+	}
+
+	for {
+		select {
+		case <-signals:
+			r.reload("SIGHUP")
+		case _, ok := <-changes:
+			if !ok {
+				changes = nil
+				continue
+			}
+			r.reload("file change")
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// reload applies a valid new config, an invalid one is
+// rejected and the current config is kept.
+func (r *reloader) reload(reason string) {
+	r.log.Infof("config reload on %s", reason)
+	cfg, err := r.loader.Reload()
+	if err == nil {
+		err = r.apply(cfg)
+	}
+	if err != nil {
+		r.log.Errorf("config reload rejected, keeping the current config:\n%v", err)
+	}
+}
+
+func (r *reloader) apply(cfg *config.Main) error {
+	changes := config.Diff(r.cfg, cfg)
+	if len(changes) == 0 {
+		r.log.Infof("config reload: no changes")
+		return nil
+	}
+
+	var (
+		reschedule bool
+		setLevel   bool
+		restart    []string
+	)
+	for _, change := range changes {
+		if !isReloadable(change.Key) {
+			restart = append(restart, change.Key)
+			continue
+		}
+		if change.Key == "logger.log-level" {
+			setLevel = true
+		} else {
+			reschedule = true
+		}
+	}
+
+	// Schedules are parsed first to not apply a config partially.
+	schedules := map[string]task.Schedule{}
+	if reschedule {
+		var errs []error
+		names := map[string]bool{}
+		for _, ns := range namespaces(cfg) {
+			names[ns.Name] = true
+			if _, ok := r.tickers[ns.Name]; !ok {
+				restart = append(restart, "namespaces["+ns.Name+"]")
+				continue
+			}
+			schedule, err := namespaceSchedule(ns, cfg.Ticker)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			schedules[ns.Name] = schedule
+		}
+		if err := errors.Join(errs...); err != nil {
+			return err
+		}
+		for name := range r.tickers {
+			if !names[name] {
+				restart = append(restart, "namespaces["+name+"]")
+			}
+		}
+	}
+
+	// A level set at runtime is replaced only by a changed config level.
+	if setLevel {
+		if _, err := r.levels.Set(cfg.Logger.LogLevel, 0); err != nil {
+			return err
+		}
+	}
+	for name, schedule := range schedules {
+		r.tickers[name].Reschedule(schedule)
+	}
+
+	for _, change := range changes {
+		r.log.Infof("config changed %s", change)
+	}
+	if len(restart) > 0 {
+		r.log.Warnf("config reloaded, restart to apply %s", strings.Join(restart, ", "))
+	}
+	r.cfg = applied(r.cfg, cfg)
+
+	return nil
+}
+
+// applied returns the running config with the reloadable settings of cfg,
+// keys needing a restart keep the running values to be reported again.
+func applied(running, cfg *config.Main) *config.Main {
+	next := *running
+	next.Ticker.Timer = cfg.Ticker.Timer
+	next.Ticker.Cron = cfg.Ticker.Cron
+	next.Ticker.TimeZone = cfg.Ticker.TimeZone
+	next.Logger.LogLevel = cfg.Logger.LogLevel
+
+	// Only schedules of running namespaces are applied,
+	// added and removed ones wait for a restart.
+	schedules := make(map[string]config.Namespace, len(cfg.Namespaces))
+	for _, ns := range cfg.Namespaces {
+		schedules[ns.Name] = ns
+	}
+	next.Namespaces = make([]config.Namespace, 0, len(running.Namespaces))
+	for _, ns := range running.Namespaces {
+		if reloaded, ok := schedules[ns.Name]; ok {
+			ns.Timer, ns.Cron = reloaded.Timer, reloaded.Cron
+		}
+		next.Namespaces = append(next.Namespaces, ns)
+	}
+
+	return &next
+}
+
+func isReloadable(key string) bool {
+	for _, k := range reloadable {
+		if k == key {
+			return true
+		}
+	}
+
+	return false
+}
+
+// namespaceSchedule returns the rotation schedule of the namespace,
+// the default timer or cron is used when neither is set.
+func namespaceSchedule(ns config.Namespace, cfg config.Ticker) (task.Schedule, error) {
+	if ns.Timer <= 0 && ns.Cron == "" {
+		ns.Timer, ns.Cron = cfg.Timer, cfg.Cron
+	}
+
+	schedule, err := task.ParseSchedule(ns.Timer, ns.Cron, cfg.TimeZone)
+	if err != nil {
+		return nil, fmt.Errorf("namespace %s: %w", ns.Name, err)
+	}
+
+	return schedule, nil
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dolefir/refresh-hash/config"
+)
+
+func Test_applied(t *testing.T) {
+	running := config.Default()
+	running.Namespaces = []config.Namespace{{Name: "csrf", Timer: time.Minute}}
+
+	cfg := config.Default()
+	cfg.Ticker.Timer = 10 * time.Minute
+	cfg.Logger.LogLevel = "debug"
+	cfg.APIServer.HTTP.ListenAddr = ":9090"
+	cfg.Namespaces = []config.Namespace{
+		{Name: "csrf", Timer: 2 * time.Minute},
+		{Name: "session", Timer: 3 * time.Minute},
+	}
+
+	got := applied(running, cfg)
+	if got.Ticker.Timer != cfg.Ticker.Timer || got.Logger.LogLevel != cfg.Logger.LogLevel {
+		t.Errorf("reloadable settings are not applied: %+v", got)
+	}
+	if want := []config.Namespace{{Name: "csrf", Timer: 2 * time.Minute}}; !reflect.DeepEqual(got.Namespaces, want) {
+		t.Errorf("wrong namespaces %v, expected - %v", got.Namespaces, want)
+	}
+	if running.Namespaces[0].Timer != time.Minute {
+		t.Error("running config is modified")
+	}
+
+	// Settings needing a restart are still reported on the next reload.
+	var keys []string
+	for _, change := range config.Diff(got, cfg) {
+		keys = append(keys, change.Key)
+	}
+	if want := []string{"api-server.http.listen-address", "namespaces"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("wrong changes %v, expected - %v", keys, want)
+	}
+}
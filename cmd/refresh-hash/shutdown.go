@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dolefir/refresh-hash/logger"
+)
+
+// component is a part of the application stopped on shutdown.
+type component struct {
+	name string
+	// stop must force the component to stop and return once ctx is done.
+	stop func(ctx context.Context) error
+}
+
+// shutdown stops components one by one sharing the timeout,
+// a component not stopped in time is forced to stop and reported.
+func shutdown(timeout time.Duration, log logger.Logger, components ...component) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var errs []error
+	for _, c := range components {
+		start := time.Now()
+		if err := c.stop(ctx); err != nil {
+			if errors.Is(err, context.DeadlineExceeded) {
+				err = fmt.Errorf("%s did not stop in %s", c.name, timeout)
+			} else {
+				err = fmt.Errorf("%s: %w", c.name, err)
+			}
+			log.Error(err)
+			errs = append(errs, err)
+			continue
+		}
+		log.Infof("%s stopped in %s", c.name, time.Since(start).Round(time.Millisecond))
+	}
+
+	return errors.Join(errs...)
+}
+
+// waitDone returns once done is closed or ctx is done.
+func waitDone(ctx context.Context, done <-chan struct{}) error {
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dolefir/refresh-hash/config"
+	"github.com/dolefir/refresh-hash/logger"
+)
+
+func Test_shutdown(t *testing.T) {
+	cfg := config.NewConfig("")
+	log := logger.NewLogger((*logger.CFGLogger)(&cfg.Logger), nil)
+
+	var stopped []string
+	stopper := func(name string, hang bool) component {
+		return component{
+			name: name,
+			stop: func(ctx context.Context) error {
+				if hang {
+					<-ctx.Done()
+					return ctx.Err()
+				}
+				stopped = append(stopped, name)
+				return nil
+			},
+		}
+	}
+
+	err := shutdown(10*time.Millisecond, log,
+		stopper("ticker", false),
+		stopper("REST API", true),
+		stopper("gRPC server", false),
+	)
+	if err == nil || !strings.Contains(err.Error(), "REST API did not stop") {
+		t.Errorf("wrong error %v, expected REST API timeout", err)
+	}
+	// Components after the timeout are still stopped.
+	if strings.Join(stopped, ",") != "ticker,gRPC server" {
+		t.Errorf("wrong stopped components %v", stopped)
+	}
+}
@@ -1,8 +1,7 @@
 package config
 
 import (
-	"errors"
-	"io"
+	"fmt"
 	"log"
 	"os"
 	"time"
@@ -12,9 +11,15 @@ import (
 
 // Main defines the properties of the application configuration.
 type Main struct {
-	APIServer APIServer `yaml:"api-server"`
-	Ticker    Ticker    `yaml:"ticker"`
-	Logger    Logger    `yaml:"logger"`
+	APIServer  APIServer   `yaml:"api-server"`
+	Ticker     Ticker      `yaml:"ticker"`
+	Logger     Logger      `yaml:"logger"`
+	Repository Repository  `yaml:"repository"`
+	Generator  Generator   `yaml:"generator"`
+	Namespaces []Namespace `yaml:"namespaces"`
+	Health     Health      `yaml:"health"`
+	AccessLog  AccessLog   `yaml:"access-log"`
+	Tracing    Tracing     `yaml:"tracing"`
 }
 
 // APIServer defines API server configuration.
@@ -28,12 +33,34 @@ type HTTP struct {
 	GracefulTimeout time.Duration `yaml:"graceful-timeout"`
 	Network         string        `yaml:"network"`
 	AddrGrpc        string        `yaml:"address-grpc"`
+	// listen address of the admin REST API, empty disables it
+	AdminAddr string `yaml:"admin-address"`
+	// listen address of the admin gRPC server, empty disables it
+	AdminAddrGrpc string `yaml:"admin-address-grpc"`
 }
 
 // Ticker defines Ticker section of the API server configuration.
 type Ticker struct {
 	Timer   time.Duration `yaml:"timer"`
 	Timeout time.Duration `yaml:"time-out"`
+	// the previous hash stays valid during the grace period after rotation
+	GracePeriod time.Duration `yaml:"grace-period"`
+	Retry       Retry         `yaml:"retry"`
+	// cron expression with optional seconds field, replaces the timer when set
+	Cron string `yaml:"cron"`
+	// time zone of the cron expression, UTC by default
+	TimeZone string `yaml:"time-zone"`
+}
+
+// Retry defines retry policy of a failed refresh.
+type Retry struct {
+	// total attempts count, 0 or 1 disables retries
+	MaxAttempts     int           `yaml:"max-attempts"`
+	InitialInterval time.Duration `yaml:"initial-interval"`
+	MaxInterval     time.Duration `yaml:"max-interval"`
+	Multiplier      float64       `yaml:"multiplier"`
+	// fraction of the interval, 0.2 is +-20%
+	Jitter float64 `yaml:"jitter"`
 }
 
 // Logger defines logger section of the API server configuration.
@@ -41,11 +68,88 @@ type Logger struct {
 	Mode      string `yaml:"mode"`
 	LogFormat string `yaml:"log-format"`
 	LogLevel  string `yaml:"log-level"`
+	// log file path, empty logs to the console only
+	File string `yaml:"file"`
+	// log level of the file, empty follows log-level
+	FileLevel string `yaml:"file-level"`
+	// max file size in megabytes before rotation, 0 disables
+	FileMaxSize int `yaml:"file-max-size"`
+	// max age of the file before rotation, 0 disables
+	FileMaxAge time.Duration `yaml:"file-max-age"`
+	// number of kept rotated files, 0 keeps all
+	FileMaxBackups int `yaml:"file-max-backups"`
+	// gzip rotated files
+	FileCompress bool `yaml:"file-compress"`
+}
+
+// Repository defines hash storage section of the application configuration.
+type Repository struct {
+	// storage type inmem/file
+	Type string `yaml:"type"`
+	// snapshot file path, used by the file storage
+	Path string `yaml:"path"`
+	// number of retained hashes, 0 is unlimited
+	HistorySize int `yaml:"history-size"`
+	// max age of retained hashes, 0 is unlimited
+	HistoryMaxAge time.Duration `yaml:"history-max-age"`
+}
+
+// Generator defines hash generator section of the application configuration.
+type Generator struct {
+	// generator type uuid4/uuid7/ulid/hex/base64url/sha256
+	Type string `yaml:"type"`
+	// random bytes count for hex/base64url
+	Bytes int `yaml:"bytes"`
+	// secret for sha256
+	Secret string `yaml:"secret"`
+}
+
+// Namespace defines a named hash rotated independently of the default one.
+type Namespace struct {
+	Name string `yaml:"name"`
+	// rotation interval, the ticker timer is used when not set
+	Timer time.Duration `yaml:"timer"`
+	// cron expression, replaces the timer when set
+	Cron string `yaml:"cron"`
+}
+
+// Health defines health checks section of the application configuration.
+type Health struct {
+	// not ready once the last rotation is older than
+	// stale-multiplier rotation intervals
+	StaleMultiplier float64 `yaml:"stale-multiplier"`
+	// how often the gRPC health status is updated
+	CheckInterval time.Duration `yaml:"check-interval"`
 }
 
-// NewConfig returns config environment reads file from config.yaml.
+// AccessLog defines REST and gRPC access log section of the application configuration.
+type AccessLog struct {
+	Enabled bool `yaml:"enabled"`
+	// REST route templates and full gRPC methods not logged
+	Skip []string `yaml:"skip"`
+	// fraction of logged requests by route, failed ones are always logged
+	Sample map[string]float64 `yaml:"sample"`
+}
+
+// Tracing defines OpenTelemetry tracing section of the application configuration.
+type Tracing struct {
+	// exporter none/otlp/stdout/file
+	Exporter string `yaml:"exporter"`
+	// OTLP gRPC collector address
+	Endpoint string `yaml:"endpoint"`
+	// OTLP connection without TLS
+	Insecure bool `yaml:"insecure"`
+	// output of the file exporter
+	Path string `yaml:"path"`
+	// fraction of traced requests without a sampled parent
+	SampleRatio float64 `yaml:"sample-ratio"`
+	ServiceName string  `yaml:"service-name"`
+}
+
+// NewConfig returns config environment reads file from config.yaml,
+// keys missing in the file keep the Default values.
 func NewConfig(configPath string) *Main {
-	cfg := &Main{}
+	cfg := Default()
 	log.Printf("configPath := %s", configPath)
 	if err := readConfigFile(configPath, cfg); err != nil {
 		log.Printf("read config file error %s", err)
@@ -55,18 +159,11 @@ func NewConfig(configPath string) *Main {
 }
 
 func readConfigFile(name string, cfg interface{}) error {
-	if _, err := os.Stat(name); os.IsNotExist(err) {
-		return errors.New("read config file error")
-	}
-
-	conf, err := os.Open(name)
-	if err != nil {
-		log.Printf("open file for reading %s: %v", name, err)
-		return err
-	}
-
-	data, err := io.ReadAll(conf)
+	data, err := os.ReadFile(name)
 	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("read config file error: %w", err)
+		}
 		log.Printf("unable to read the %s: %v", name, err)
 		return err
 	}
@@ -0,0 +1,182 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPrefix is the prefix of environment variables overriding the config,
+// ticker.timer is overridden by REFRESH_HASH_TICKER_TIMER.
+const EnvPrefix = "REFRESH_HASH_"
+
+// Loader reads the config file given by the -config flag overridden
+// by environment variables and then by command line flags named
+// after the config keys, e.g. -ticker.timer=1m. It keeps the command
+// line flags so a reload applies the same overrides.
+type Loader struct {
+	path  string
+	flags map[string]string
+}
+
+// NewLoader returns a Loader of the command line.
+func NewLoader(args []string) (*Loader, error) {
+	fs := flag.NewFlagSet("refresh-hash", flag.ExitOnError)
+	path, ok := os.LookupEnv(EnvPrefix + "CONFIG")
+	if !ok {
+		path = "config.yaml"
+	}
+	configPath := fs.String("config", path, "Configuration file, REFRESH_HASH_CONFIG")
+
+	flags := map[string]string{}
+	for _, f := range fields(&Main{}) {
+		key := f.key()
+		fs.Func(key, "overrides "+f.env(), func(value string) error {
+			flags[key] = value
+			return nil
+		})
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	return &Loader{path: *configPath, flags: flags}, nil
+}
+
+// Path returns the config file path.
+func (l *Loader) Path() string {
+	return l.path
+}
+
+// Load reads the config file, a missing one leaves the defaults,
+// then applies environment variables and flags and validates the result.
+func (l *Loader) Load() (*Main, error) {
+	return l.load(false)
+}
+
+// Reload is Load of a running process, a missing file is an error
+// so a file being replaced does not reset the config to the defaults.
+func (l *Loader) Reload() (*Main, error) {
+	return l.load(true)
+}
+
+func (l *Loader) load(requireFile bool) (*Main, error) {
+	cfg := Default()
+	if err := readConfigFile(l.path, cfg); err != nil {
+		if requireFile || !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		log.Printf("config file %s not found, using defaults", l.path)
+	}
+
+	err := cfg.override(envLookup(os.LookupEnv), func(f field) (string, bool) {
+		value, ok := l.flags[f.key()]
+		return value, ok
+	})
+	if err := errors.Join(err, cfg.Validate()); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// override sets fields found by lookups, a later lookup takes precedence.
+func (m *Main) override(lookups ...func(f field) (string, bool)) error {
+	var errs []error
+	for _, f := range fields(m) {
+		for _, lookup := range lookups {
+			value, ok := lookup(f)
+			if !ok {
+				continue
+			}
+			if err := f.set(value); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", f.key(), err))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// envLookup looks up a field by its environment variable.
+func envLookup(lookupEnv func(string) (string, bool)) func(f field) (string, bool) {
+	return func(f field) (string, bool) {
+		return lookupEnv(f.env())
+	}
+}
+
+// field is a config value addressed by its yaml keys,
+// a struct is walked into, anything else including slices is a field.
+type field struct {
+	path  []string
+	value reflect.Value
+}
+
+func fields(cfg *Main) []field {
+	return walk(nil, reflect.ValueOf(cfg).Elem())
+}
+
+func walk(path []string, v reflect.Value) []field {
+	var out []field
+	for i := 0; i < v.NumField(); i++ {
+		tag, _, _ := strings.Cut(v.Type().Field(i).Tag.Get("yaml"), ",")
+		if tag == "" || tag == "-" {
+			continue
+		}
+		fieldPath := append(append([]string{}, path...), tag)
+		if v.Field(i).Kind() == reflect.Struct {
+			out = append(out, walk(fieldPath, v.Field(i))...)
+			continue
+		}
+		out = append(out, field{path: fieldPath, value: v.Field(i)})
+	}
+
+	return out
+}
+
+// key returns the dotted yaml path, e.g. api-server.http.listen-address.
+func (f field) key() string {
+	return strings.Join(f.path, ".")
+}
+
+// env returns the environment variable, e.g. REFRESH_HASH_API_SERVER_HTTP_LISTEN_ADDRESS.
+func (f field) env() string {
+	name := strings.ToUpper(strings.Join(f.path, "_"))
+	return EnvPrefix + strings.ReplaceAll(name, "-", "_")
+}
+
+// set parses value as yaml of the field type,
+// a string is taken as is.
+func (f field) set(value string) error {
+	switch f.value.Interface().(type) {
+	case string:
+		f.value.SetString(value)
+		return nil
+	case time.Duration:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		f.value.SetInt(int64(d))
+		return nil
+	}
+
+	parsed := reflect.New(f.value.Type())
+	if err := yaml.Unmarshal([]byte(value), parsed.Interface()); err != nil {
+		var typeErr *yaml.TypeError
+		if errors.As(err, &typeErr) {
+			return fmt.Errorf("invalid value %q", value)
+		}
+		return err
+	}
+	f.value.Set(parsed.Elem())
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func load(t *testing.T, args []string) (*Main, error) {
+	t.Helper()
+
+	loader, err := NewLoader(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return loader.Load()
+}
+
+func TestLoader_Load(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	data := []byte("ticker:\n  timer: 5m\n  time-out: 10s\nlogger:\n  log-level: info\ngenerator:\n  type: uuid4\n")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("REFRESH_HASH_TICKER_TIMER", "1m")
+	t.Setenv("REFRESH_HASH_LOGGER_LOG_LEVEL", "debug")
+	t.Setenv("REFRESH_HASH_API_SERVER_HTTP_LISTEN_ADDRESS", ":9090")
+	t.Setenv("REFRESH_HASH_NAMESPACES", "[{name: csrf, timer: 30s}]")
+
+	cfg, err := load(t, []string{"-config", path, "-logger.log-level=error", "-generator.bytes", "16"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		got  interface{}
+		want interface{}
+	}{
+		{name: "file", got: cfg.Ticker.Timeout, want: 10 * time.Second},
+		{name: "file", got: cfg.Generator.Type, want: "uuid4"},
+		{name: "env over file", got: cfg.Ticker.Timer, want: time.Minute},
+		{name: "env", got: cfg.APIServer.HTTP.ListenAddr, want: ":9090"},
+		{name: "env slice", got: cfg.Namespaces, want: []Namespace{{Name: "csrf", Timer: 30 * time.Second}}},
+		{name: "flag over env", got: cfg.Logger.LogLevel, want: "error"},
+		{name: "flag", got: cfg.Generator.Bytes, want: 16},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !reflect.DeepEqual(tt.got, tt.want) {
+				t.Errorf("got %v, want %v", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoader_LoadInvalidEnv(t *testing.T) {
+	t.Setenv("REFRESH_HASH_TICKER_TIMER", "soon")
+	t.Setenv("REFRESH_HASH_REPOSITORY_HISTORY_SIZE", "many")
+
+	_, err := load(t, []string{"-config", filepath.Join(t.TempDir(), "missing.yaml")})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	for _, key := range []string{"ticker.timer", "repository.history-size"} {
+		if !strings.Contains(err.Error(), key) {
+			t.Errorf("error %q does not mention %s", err, key)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+	"reflect"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Change is a config key changed by a reload.
+type Change struct {
+	Key string
+	Old interface{}
+	New interface{}
+}
+
+// String returns the change as "key: old -> new".
+func (c Change) String() string {
+	return fmt.Sprintf("%s: %v -> %v", c.Key, c.Old, c.New)
+}
+
+// Diff returns keys changed between configs, secrets are masked.
+func Diff(old, new *Main) []Change {
+	oldFields, newFields := fields(old), fields(new)
+
+	var changes []Change
+	for i := range oldFields {
+		o, n := oldFields[i].value.Interface(), newFields[i].value.Interface()
+		if reflect.DeepEqual(o, n) {
+			continue
+		}
+		if oldFields[i].path[len(oldFields[i].path)-1] == "secret" {
+			o, n = "***", "***"
+		}
+		changes = append(changes, Change{Key: oldFields[i].key(), Old: o, New: n})
+	}
+
+	return changes
+}
+
+// Watch signals on the returned channel when the config file is
+// written, events within debounce are coalesced. The channel is
+// closed once ctx is done.
+func (l *Loader) Watch(ctx context.Context, debounce time.Duration) (<-chan struct{}, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	// The directory is watched as editors replace the file by rename.
+	if err := watcher.Add(filepath.Dir(l.path)); err != nil {
+		watcher.Close()
+		return nil, err
+	}
+
+	path := filepath.Clean(l.path)
+	changes := make(chan struct{}, 1)
+	go func() {
+		defer close(changes)
+		defer watcher.Close()
+
+		timer := time.NewTimer(debounce)
+		timer.Stop()
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if filepath.Clean(event.Name) == path && event.Has(fsnotify.Write|fsnotify.Create) {
+					timer.Reset(debounce)
+				}
+
+			case <-timer.C:
+				select {
+				case changes <- struct{}{}:
+				default:
+				}
+
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				log.Printf("watch config file %s: %v", l.path, err)
+
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return changes, nil
+}
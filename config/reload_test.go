@@ -0,0 +1,51 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDiff(t *testing.T) {
+	old := Default()
+	changed := Default()
+	changed.Ticker.Timer = time.Minute
+	changed.Logger.LogLevel = "debug"
+	changed.Generator.Secret = "s3cret"
+
+	want := []string{
+		"ticker.timer: 5m0s -> 1m0s",
+		"logger.log-level: info -> debug",
+		"generator.secret: *** -> ***",
+	}
+
+	var got []string
+	for _, change := range Diff(old, changed) {
+		got = append(got, change.String())
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("wrong diff %q, expected - %q", got, want)
+	}
+
+	if changes := Diff(old, Default()); len(changes) != 0 {
+		t.Errorf("unexpected changes %v", changes)
+	}
+}
+
+func TestLoader_ReloadMissingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	loader, err := NewLoader([]string{"-config", path})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := loader.Load(); err != nil {
+		t.Errorf("Loader.Load() error = %v, expected the defaults", err)
+	}
+	if _, err := loader.Reload(); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Loader.Reload() error = %v, expected not exist", err)
+	}
+}
@@ -0,0 +1,266 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/dolefir/refresh-hash/models"
+	"github.com/dolefir/refresh-hash/task/crontab"
+)
+
+// Default returns the configuration used for keys missing in the file.
+func Default() *Main {
+	return &Main{
+		APIServer: APIServer{
+			HTTP: HTTP{
+				ListenAddr:      ":8080",
+				GracefulTimeout: 30 * time.Second,
+				Network:         "tcp",
+				AddrGrpc:        ":8081",
+			},
+		},
+		Ticker: Ticker{
+			Timer:   5 * time.Minute,
+			Timeout: 30 * time.Second,
+			Retry: Retry{
+				MaxAttempts:     3,
+				InitialInterval: time.Second,
+				MaxInterval:     30 * time.Second,
+				Multiplier:      2,
+				Jitter:          0.2,
+			},
+			TimeZone: "UTC",
+		},
+		Logger: Logger{
+			Mode:           "dev",
+			LogFormat:      "text",
+			LogLevel:       "info",
+			FileMaxSize:    100,
+			FileMaxAge:     24 * time.Hour,
+			FileMaxBackups: 7,
+			FileCompress:   true,
+		},
+		Repository: Repository{
+			Type:          "inmem",
+			Path:          "data/hash.json",
+			HistorySize:   100,
+			HistoryMaxAge: 24 * time.Hour,
+		},
+		Generator: Generator{
+			Type:  "uuid4",
+			Bytes: 32,
+		},
+		Health: Health{
+			StaleMultiplier: 2,
+			CheckInterval:   5 * time.Second,
+		},
+		AccessLog: AccessLog{
+			Enabled: true,
+			Skip: []string{
+				"/healthz",
+				"/readyz",
+				"/metrics",
+				"/grpc.health.v1.Health/Check",
+				"/grpc.health.v1.Health/Watch",
+			},
+		},
+		Tracing: Tracing{
+			Exporter:    "none",
+			Endpoint:    "localhost:4317",
+			Insecure:    true,
+			Path:        "data/traces.json",
+			SampleRatio: 1,
+			ServiceName: "refresh-hash",
+		},
+	}
+}
+
+// Validate returns all problems of the configuration joined.
+func (m *Main) Validate() error {
+	var v validator
+
+	http := m.APIServer.HTTP
+	v.address("api-server.http.listen-address", http.ListenAddr)
+	v.address("api-server.http.address-grpc", http.AddrGrpc)
+	// Admin endpoints change the service, they are not
+	// served next to the public ones.
+	if http.AdminAddr != "" {
+		v.address("api-server.http.admin-address", http.AdminAddr)
+		if http.AdminAddr == http.ListenAddr || http.AdminAddr == http.AddrGrpc {
+			v.errorf("api-server.http.admin-address %q must differ from the public addresses", http.AdminAddr)
+		}
+	}
+	if http.AdminAddrGrpc != "" {
+		v.address("api-server.http.admin-address-grpc", http.AdminAddrGrpc)
+		if http.AdminAddrGrpc == http.ListenAddr || http.AdminAddrGrpc == http.AddrGrpc || http.AdminAddrGrpc == http.AdminAddr {
+			v.errorf("api-server.http.admin-address-grpc %q must differ from the other addresses", http.AdminAddrGrpc)
+		}
+	}
+	v.oneOf("api-server.http.network", http.Network, "tcp", "tcp4", "tcp6")
+	v.positive("api-server.http.graceful-timeout", http.GracefulTimeout)
+
+	ticker := m.Ticker
+	if ticker.Cron == "" {
+		v.positive("ticker.timer", ticker.Timer)
+	}
+	v.positive("ticker.time-out", ticker.Timeout)
+	if ticker.Cron == "" && ticker.Timer > 0 && ticker.Timeout >= ticker.Timer {
+		v.errorf("ticker.time-out %s must be less than ticker.timer %s", ticker.Timeout, ticker.Timer)
+	}
+	v.notNegative("ticker.grace-period", ticker.GracePeriod)
+	if _, err := time.LoadLocation(ticker.TimeZone); err != nil {
+		v.errorf("ticker.time-zone: %v", err)
+	}
+	v.cron("ticker.cron", ticker.Cron)
+
+	retry := ticker.Retry
+	if retry.MaxAttempts < 0 {
+		v.errorf("ticker.retry.max-attempts must not be negative, got %d", retry.MaxAttempts)
+	}
+	v.notNegative("ticker.retry.initial-interval", retry.InitialInterval)
+	v.notNegative("ticker.retry.max-interval", retry.MaxInterval)
+	if retry.Multiplier < 0 {
+		v.errorf("ticker.retry.multiplier must not be negative, got %g", retry.Multiplier)
+	}
+	if retry.Jitter < 0 || retry.Jitter > 1 {
+		v.errorf("ticker.retry.jitter must be between 0 and 1, got %g", retry.Jitter)
+	}
+
+	v.oneOf("logger.mode", m.Logger.Mode, "dev", "prod")
+	v.oneOf("logger.log-format", m.Logger.LogFormat, "text", "json")
+	v.oneOf("logger.log-level", m.Logger.LogLevel, "debug", "info", "warn", "error")
+	if m.Logger.FileLevel != "" {
+		v.oneOf("logger.file-level", m.Logger.FileLevel, "debug", "info", "warn", "error")
+	}
+	if m.Logger.FileMaxSize < 0 {
+		v.errorf("logger.file-max-size must not be negative, got %d", m.Logger.FileMaxSize)
+	}
+	v.notNegative("logger.file-max-age", m.Logger.FileMaxAge)
+	if m.Logger.FileMaxBackups < 0 {
+		v.errorf("logger.file-max-backups must not be negative, got %d", m.Logger.FileMaxBackups)
+	}
+
+	v.oneOf("repository.type", m.Repository.Type, "inmem", "file")
+	if m.Repository.Type == "file" && m.Repository.Path == "" {
+		v.errorf("repository.path is required by the file repository")
+	}
+	if m.Repository.HistorySize < 0 {
+		v.errorf("repository.history-size must not be negative, got %d", m.Repository.HistorySize)
+	}
+	v.notNegative("repository.history-max-age", m.Repository.HistoryMaxAge)
+	// The grace period validates the previous hash, it must be retained.
+	if ticker.GracePeriod > 0 && m.Repository.HistorySize > 0 && m.Repository.HistorySize < 2 {
+		v.errorf("repository.history-size %d must be at least 2 with ticker.grace-period %s", m.Repository.HistorySize, ticker.GracePeriod)
+	}
+
+	v.oneOf("generator.type", m.Generator.Type, "uuid4", "uuid7", "ulid", "hex", "base64url", "sha256")
+	if (m.Generator.Type == "hex" || m.Generator.Type == "base64url") && m.Generator.Bytes <= 0 {
+		v.errorf("generator.bytes must be positive, got %d", m.Generator.Bytes)
+	}
+
+	names := make(map[string]bool, len(models.ReservedNamespaces)+len(m.Namespaces))
+	for _, name := range models.ReservedNamespaces {
+		names[name] = true
+	}
+	for i, ns := range m.Namespaces {
+		key := fmt.Sprintf("namespaces[%d]", i)
+		switch {
+		case ns.Name == "":
+			v.errorf("%s.name is required", key)
+		case names[ns.Name]:
+			v.errorf("%s.name %q is duplicated or reserved", key, ns.Name)
+		}
+		names[ns.Name] = true
+		v.notNegative(key+".timer", ns.Timer)
+		if ns.Cron == "" && ns.Timer > 0 && ticker.Timeout >= ns.Timer {
+			v.errorf("ticker.time-out %s must be less than %s.timer %s", ticker.Timeout, key, ns.Timer)
+		}
+		v.cron(key+".cron", ns.Cron)
+	}
+
+	if m.Health.StaleMultiplier < 1 {
+		v.errorf("health.stale-multiplier must be at least 1, got %g", m.Health.StaleMultiplier)
+	}
+	v.positive("health.check-interval", m.Health.CheckInterval)
+
+	routes := make([]string, 0, len(m.AccessLog.Sample))
+	for route := range m.AccessLog.Sample {
+		routes = append(routes, route)
+	}
+	sort.Strings(routes)
+	for _, route := range routes {
+		if rate := m.AccessLog.Sample[route]; rate < 0 || rate > 1 {
+			v.errorf("access-log.sample[%s] must be between 0 and 1, got %g", route, rate)
+		}
+	}
+
+	tracing := m.Tracing
+	v.oneOf("tracing.exporter", tracing.Exporter, "none", "otlp", "stdout", "file")
+	if tracing.Exporter == "otlp" && tracing.Endpoint == "" {
+		v.errorf("tracing.endpoint is required by the otlp exporter")
+	}
+	if tracing.Exporter == "file" && tracing.Path == "" {
+		v.errorf("tracing.path is required by the file exporter")
+	}
+	if tracing.SampleRatio < 0 || tracing.SampleRatio > 1 {
+		v.errorf("tracing.sample-ratio must be between 0 and 1, got %g", tracing.SampleRatio)
+	}
+
+	return errors.Join(v.errs...)
+}
+
+// validator collects problems of the configuration.
+type validator struct {
+	errs []error
+}
+
+func (v *validator) errorf(format string, args ...interface{}) {
+	v.errs = append(v.errs, fmt.Errorf(format, args...))
+}
+
+func (v *validator) positive(key string, d time.Duration) {
+	if d <= 0 {
+		v.errorf("%s must be positive, got %s", key, d)
+	}
+}
+
+func (v *validator) notNegative(key string, d time.Duration) {
+	if d < 0 {
+		v.errorf("%s must not be negative, got %s", key, d)
+	}
+}
+
+func (v *validator) oneOf(key, value string, allowed ...string) {
+	for _, a := range allowed {
+		if value == a {
+			return
+		}
+	}
+	v.errorf("%s %q must be one of %v", key, value, allowed)
+}
+
+// cron checks a cron expression, empty is not set.
+func (v *validator) cron(key, expr string) {
+	if expr == "" {
+		return
+	}
+	if _, err := crontab.Parser.Parse(expr); err != nil {
+		v.errorf("%s: %v", key, err)
+	}
+}
+
+// address checks a host:port listen address, the host may be empty.
+func (v *validator) address(key, addr string) {
+	_, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		v.errorf("%s %q: %v", key, addr, err)
+		return
+	}
+	if n, err := strconv.Atoi(port); err != nil || n < 0 || n > 65535 {
+		v.errorf("%s %q: invalid port %q", key, addr, port)
+	}
+}
@@ -0,0 +1,130 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMain_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(cfg *Main)
+		wantErr []string
+	}{
+		{
+			name:   "defaults are valid",
+			modify: func(cfg *Main) {},
+		},
+		{
+			name: "cron replaces timer",
+			modify: func(cfg *Main) {
+				cfg.Ticker.Timer = 0
+				cfg.Ticker.Cron = "@hourly"
+			},
+		},
+		{
+			name: "all problems are listed",
+			modify: func(cfg *Main) {
+				cfg.Ticker.Timer = 0
+				cfg.Logger.LogLevel = "verbose"
+				cfg.APIServer.HTTP.ListenAddr = "8080"
+				cfg.APIServer.HTTP.AddrGrpc = ":http-grpc"
+			},
+			wantErr: []string{
+				"ticker.timer must be positive",
+				"logger.log-level",
+				"api-server.http.listen-address",
+				"api-server.http.address-grpc",
+			},
+		},
+		{
+			name: "timeout not less than timer",
+			modify: func(cfg *Main) {
+				cfg.Ticker.Timer = time.Minute
+				cfg.Ticker.Timeout = time.Minute
+			},
+			wantErr: []string{"ticker.time-out 1m0s must be less than ticker.timer 1m0s"},
+		},
+		{
+			name: "duplicated namespaces",
+			modify: func(cfg *Main) {
+				cfg.Namespaces = []Namespace{{Name: "csrf"}, {Name: "csrf"}, {Name: "default"}}
+			},
+			wantErr: []string{"namespaces[1].name", "namespaces[2].name"},
+		},
+		{
+			name: "namespace timer not over timeout",
+			modify: func(cfg *Main) {
+				cfg.Ticker.Timeout = time.Minute
+				cfg.Namespaces = []Namespace{{Name: "csrf", Timer: 30 * time.Second}, {Name: "cache", Timer: 2 * time.Minute}}
+			},
+			wantErr: []string{"ticker.time-out 1m0s must be less than namespaces[0].timer 30s"},
+		},
+		{
+			name: "admin addresses",
+			modify: func(cfg *Main) {
+				cfg.APIServer.HTTP.AdminAddr = cfg.APIServer.HTTP.ListenAddr
+				cfg.APIServer.HTTP.AdminAddrGrpc = "localhost"
+			},
+			wantErr: []string{"api-server.http.admin-address \":8080\" must differ", "api-server.http.admin-address-grpc"},
+		},
+		{
+			name: "grace period without previous hash",
+			modify: func(cfg *Main) {
+				cfg.Ticker.GracePeriod = 30 * time.Second
+				cfg.Repository.HistorySize = 1
+			},
+			wantErr: []string{"repository.history-size 1 must be at least 2"},
+		},
+		{
+			name: "malformed cron",
+			modify: func(cfg *Main) {
+				cfg.Ticker.Cron = "every day"
+				cfg.Namespaces = []Namespace{{Name: "csrf", Cron: "61 * * * *"}, {Name: "cache", Cron: "@hourly"}}
+			},
+			wantErr: []string{"ticker.cron", "namespaces[0].cron"},
+		},
+		{
+			name: "namespaces clashing with routes",
+			modify: func(cfg *Main) {
+				cfg.Namespaces = []Namespace{{Name: "history"}, {Name: "stream"}, {Name: "refresh"}, {Name: "validate"}}
+			},
+			wantErr: []string{"namespaces[0].name", "namespaces[1].name", "namespaces[2].name", "namespaces[3].name"},
+		},
+		{
+			name: "tracing file exporter",
+			modify: func(cfg *Main) {
+				cfg.Tracing.Exporter = "file"
+				cfg.Tracing.Path = ""
+				cfg.Tracing.SampleRatio = 1.5
+			},
+			wantErr: []string{"tracing.path is required", "tracing.sample-ratio"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			tt.modify(cfg)
+
+			err := cfg.Validate()
+			if len(tt.wantErr) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if got := len(strings.Split(err.Error(), "\n")); got != len(tt.wantErr) {
+				t.Errorf("wrong number of problems (%d), expected - %d: %v", got, len(tt.wantErr), err)
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error %q does not contain %q", err, want)
+				}
+			}
+		})
+	}
+}
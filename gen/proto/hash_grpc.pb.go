@@ -23,6 +23,9 @@ const _ = grpc.SupportPackageIsVersion7
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type HashServiceClient interface {
 	GetHash(ctx context.Context, in *GetHashRequest, opts ...grpc.CallOption) (*GetHashResponse, error)
+	ValidateHash(ctx context.Context, in *ValidateHashRequest, opts ...grpc.CallOption) (*ValidateHashResponse, error)
+	RefreshHash(ctx context.Context, in *RefreshHashRequest, opts ...grpc.CallOption) (*RefreshHashResponse, error)
+	WatchHash(ctx context.Context, in *WatchHashRequest, opts ...grpc.CallOption) (HashService_WatchHashClient, error)
 }
 
 type hashServiceClient struct {
@@ -42,11 +45,64 @@ func (c *hashServiceClient) GetHash(ctx context.Context, in *GetHashRequest, opt
 	return out, nil
 }
 
+func (c *hashServiceClient) ValidateHash(ctx context.Context, in *ValidateHashRequest, opts ...grpc.CallOption) (*ValidateHashResponse, error) {
+	out := new(ValidateHashResponse)
+	err := c.cc.Invoke(ctx, "/HashService/ValidateHash", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hashServiceClient) RefreshHash(ctx context.Context, in *RefreshHashRequest, opts ...grpc.CallOption) (*RefreshHashResponse, error) {
+	out := new(RefreshHashResponse)
+	err := c.cc.Invoke(ctx, "/HashService/RefreshHash", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hashServiceClient) WatchHash(ctx context.Context, in *WatchHashRequest, opts ...grpc.CallOption) (HashService_WatchHashClient, error) {
+	stream, err := c.cc.NewStream(ctx, &HashService_ServiceDesc.Streams[0], "/HashService/WatchHash", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &hashServiceWatchHashClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type HashService_WatchHashClient interface {
+	Recv() (*WatchHashResponse, error)
+	grpc.ClientStream
+}
+
+type hashServiceWatchHashClient struct {
+	grpc.ClientStream
+}
+
+func (x *hashServiceWatchHashClient) Recv() (*WatchHashResponse, error) {
+	m := new(WatchHashResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // HashServiceServer is the server API for HashService service.
 // All implementations must embed UnimplementedHashServiceServer
 // for forward compatibility
 type HashServiceServer interface {
 	GetHash(context.Context, *GetHashRequest) (*GetHashResponse, error)
+	ValidateHash(context.Context, *ValidateHashRequest) (*ValidateHashResponse, error)
+	RefreshHash(context.Context, *RefreshHashRequest) (*RefreshHashResponse, error)
+	WatchHash(*WatchHashRequest, HashService_WatchHashServer) error
 	mustEmbedUnimplementedHashServiceServer()
 }
 
@@ -57,6 +113,15 @@ type UnimplementedHashServiceServer struct {
 func (UnimplementedHashServiceServer) GetHash(context.Context, *GetHashRequest) (*GetHashResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetHash not implemented")
 }
+func (UnimplementedHashServiceServer) ValidateHash(context.Context, *ValidateHashRequest) (*ValidateHashResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ValidateHash not implemented")
+}
+func (UnimplementedHashServiceServer) RefreshHash(context.Context, *RefreshHashRequest) (*RefreshHashResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RefreshHash not implemented")
+}
+func (UnimplementedHashServiceServer) WatchHash(*WatchHashRequest, HashService_WatchHashServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchHash not implemented")
+}
 func (UnimplementedHashServiceServer) mustEmbedUnimplementedHashServiceServer() {}
 
 // UnsafeHashServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -88,6 +153,63 @@ func _HashService_GetHash_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _HashService_ValidateHash_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ValidateHashRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HashServiceServer).ValidateHash(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/HashService/ValidateHash",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HashServiceServer).ValidateHash(ctx, req.(*ValidateHashRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HashService_RefreshHash_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RefreshHashRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HashServiceServer).RefreshHash(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/HashService/RefreshHash",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HashServiceServer).RefreshHash(ctx, req.(*RefreshHashRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HashService_WatchHash_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchHashRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(HashServiceServer).WatchHash(m, &hashServiceWatchHashServer{stream})
+}
+
+type HashService_WatchHashServer interface {
+	Send(*WatchHashResponse) error
+	grpc.ServerStream
+}
+
+type hashServiceWatchHashServer struct {
+	grpc.ServerStream
+}
+
+func (x *hashServiceWatchHashServer) Send(m *WatchHashResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // HashService_ServiceDesc is the grpc.ServiceDesc for HashService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -99,6 +221,142 @@ var HashService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetHash",
 			Handler:    _HashService_GetHash_Handler,
 		},
+		{
+			MethodName: "ValidateHash",
+			Handler:    _HashService_ValidateHash_Handler,
+		},
+		{
+			MethodName: "RefreshHash",
+			Handler:    _HashService_RefreshHash_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchHash",
+			Handler:       _HashService_WatchHash_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "proto/hash.proto",
+}
+
+// AdminServiceClient is the client API for AdminService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type AdminServiceClient interface {
+	GetLogLevel(ctx context.Context, in *GetLogLevelRequest, opts ...grpc.CallOption) (*LogLevelResponse, error)
+	SetLogLevel(ctx context.Context, in *SetLogLevelRequest, opts ...grpc.CallOption) (*LogLevelResponse, error)
+}
+
+type adminServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAdminServiceClient(cc grpc.ClientConnInterface) AdminServiceClient {
+	return &adminServiceClient{cc}
+}
+
+func (c *adminServiceClient) GetLogLevel(ctx context.Context, in *GetLogLevelRequest, opts ...grpc.CallOption) (*LogLevelResponse, error) {
+	out := new(LogLevelResponse)
+	err := c.cc.Invoke(ctx, "/AdminService/GetLogLevel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) SetLogLevel(ctx context.Context, in *SetLogLevelRequest, opts ...grpc.CallOption) (*LogLevelResponse, error) {
+	out := new(LogLevelResponse)
+	err := c.cc.Invoke(ctx, "/AdminService/SetLogLevel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AdminServiceServer is the server API for AdminService service.
+// All implementations must embed UnimplementedAdminServiceServer
+// for forward compatibility
+type AdminServiceServer interface {
+	GetLogLevel(context.Context, *GetLogLevelRequest) (*LogLevelResponse, error)
+	SetLogLevel(context.Context, *SetLogLevelRequest) (*LogLevelResponse, error)
+	mustEmbedUnimplementedAdminServiceServer()
+}
+
+// UnimplementedAdminServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedAdminServiceServer struct {
+}
+
+func (UnimplementedAdminServiceServer) GetLogLevel(context.Context, *GetLogLevelRequest) (*LogLevelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLogLevel not implemented")
+}
+func (UnimplementedAdminServiceServer) SetLogLevel(context.Context, *SetLogLevelRequest) (*LogLevelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetLogLevel not implemented")
+}
+func (UnimplementedAdminServiceServer) mustEmbedUnimplementedAdminServiceServer() {}
+
+// UnsafeAdminServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AdminServiceServer will
+// result in compilation errors.
+type UnsafeAdminServiceServer interface {
+	mustEmbedUnimplementedAdminServiceServer()
+}
+
+func RegisterAdminServiceServer(s grpc.ServiceRegistrar, srv AdminServiceServer) {
+	s.RegisterService(&AdminService_ServiceDesc, srv)
+}
+
+func _AdminService_GetLogLevel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLogLevelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).GetLogLevel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/AdminService/GetLogLevel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).GetLogLevel(ctx, req.(*GetLogLevelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_SetLogLevel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetLogLevelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).SetLogLevel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/AdminService/SetLogLevel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).SetLogLevel(ctx, req.(*SetLogLevelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AdminService_ServiceDesc is the grpc.ServiceDesc for AdminService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AdminService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "AdminService",
+	HandlerType: (*AdminServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetLogLevel",
+			Handler:    _AdminService_GetLogLevel_Handler,
+		},
+		{
+			MethodName: "SetLogLevel",
+			Handler:    _AdminService_SetLogLevel_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/hash.proto",
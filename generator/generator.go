@@ -0,0 +1,179 @@
+package generator
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/oklog/ulid/v2"
+)
+
+// Generator types selectable in the config.
+const (
+	TypeUUIDv4    = "uuid4"
+	TypeUUIDv7    = "uuid7"
+	TypeULID      = "ulid"
+	TypeHex       = "hex"
+	TypeBase64URL = "base64url"
+	TypeSHA256    = "sha256"
+)
+
+// defaultBytes is the random bytes count for the hex/base64url generators.
+const defaultBytes = 32
+
+// Generator is the interface that wraps creation of a new hash value.
+type Generator interface {
+	// Generate returns a new hash value.
+	Generate() (string, error)
+	// Type returns the generator type stored with the hash.
+	Type() string
+}
+
+type CFGGenerator struct {
+	// generator type uuid4/uuid7/ulid/hex/base64url/sha256
+	Type string
+	// random bytes count for hex/base64url
+	Bytes int
+	// secret for sha256
+	Secret string
+}
+
+// New returns the generator selected in the config,
+// uuid4 is used when the type is not set.
+func New(cfg *CFGGenerator) (Generator, error) {
+	bytes := cfg.Bytes
+	if bytes <= 0 {
+		bytes = defaultBytes
+	}
+
+	switch cfg.Type {
+	case TypeUUIDv4, "":
+		return UUIDv4{}, nil
+	case TypeUUIDv7:
+		return UUIDv7{}, nil
+	case TypeULID:
+		return ULID{}, nil
+	case TypeHex:
+		return Random{Bytes: bytes, Encode: hex.EncodeToString, Name: TypeHex}, nil
+	case TypeBase64URL:
+		return Random{Bytes: bytes, Encode: base64.RawURLEncoding.EncodeToString, Name: TypeBase64URL}, nil
+	case TypeSHA256:
+		if cfg.Secret == "" {
+			return nil, errors.New("generator: sha256 requires a secret")
+		}
+		return NewSHA256(cfg.Secret, uint64(time.Now().UnixNano())), nil
+	default:
+		return nil, fmt.Errorf("generator: unknown type %q", cfg.Type)
+	}
+}
+
+// UUIDv4 generates random UUIDs.
+type UUIDv4 struct{}
+
+// Generate returns a new hash value.
+func (UUIDv4) Generate() (string, error) {
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return "", err
+	}
+
+	return id.String(), nil
+}
+
+// Type returns the generator type.
+func (UUIDv4) Type() string { return TypeUUIDv4 }
+
+// UUIDv7 generates time-ordered UUIDs.
+type UUIDv7 struct{}
+
+// Generate returns a new hash value.
+func (UUIDv7) Generate() (string, error) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return "", err
+	}
+
+	return id.String(), nil
+}
+
+// Type returns the generator type.
+func (UUIDv7) Type() string { return TypeUUIDv7 }
+
+// ULID generates lexicographically sortable identifiers.
+type ULID struct{}
+
+// Generate returns a new hash value.
+func (ULID) Generate() (string, error) {
+	id, err := ulid.New(ulid.Now(), rand.Reader)
+	if err != nil {
+		return "", err
+	}
+
+	return id.String(), nil
+}
+
+// Type returns the generator type.
+func (ULID) Type() string { return TypeULID }
+
+// Random generates N random bytes rendered by Encode.
+type Random struct {
+	Bytes  int
+	Encode func([]byte) string
+	Name   string
+}
+
+// Generate returns a new hash value.
+func (r Random) Generate() (string, error) {
+	buf := make([]byte, r.Bytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return r.Encode(buf), nil
+}
+
+// Type returns the generator type.
+func (r Random) Type() string { return r.Name }
+
+// SHA256 generates hex encoded SHA-256 of a secret plus counter.
+type SHA256 struct {
+	secret  []byte
+	counter uint64
+	mu      sync.Mutex
+}
+
+// NewSHA256 returns SHA256 generator starting from the counter,
+// seed it with something monotonic to not repeat values on restart.
+func NewSHA256(secret string, counter uint64) *SHA256 {
+	return &SHA256{
+		secret:  []byte(secret),
+		counter: counter,
+	}
+}
+
+// Generate returns a new hash value.
+func (s *SHA256) Generate() (string, error) {
+	s.mu.Lock()
+	s.counter++
+	counter := s.counter
+	s.mu.Unlock()
+
+	var c [8]byte
+	binary.BigEndian.PutUint64(c[:], counter)
+
+	h := sha256.New()
+	h.Write(s.secret)
+	h.Write(c[:])
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Type returns the generator type.
+func (s *SHA256) Type() string { return TypeSHA256 }
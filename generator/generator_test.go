@@ -0,0 +1,82 @@
+package generator
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     CFGGenerator
+		pattern string
+		wantErr bool
+	}{
+		{
+			name:    "should returns uuid4 by default",
+			cfg:     CFGGenerator{},
+			pattern: `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[0-9a-f]{4}-[0-9a-f]{12}$`,
+		},
+		{
+			name:    "should returns uuid7",
+			cfg:     CFGGenerator{Type: TypeUUIDv7},
+			pattern: `^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[0-9a-f]{4}-[0-9a-f]{12}$`,
+		},
+		{
+			name:    "should returns ulid",
+			cfg:     CFGGenerator{Type: TypeULID},
+			pattern: `^[0-9A-HJKMNP-TV-Z]{26}$`,
+		},
+		{
+			name:    "should returns hex of N bytes",
+			cfg:     CFGGenerator{Type: TypeHex, Bytes: 16},
+			pattern: `^[0-9a-f]{32}$`,
+		},
+		{
+			name:    "should returns base64url of default bytes",
+			cfg:     CFGGenerator{Type: TypeBase64URL},
+			pattern: `^[A-Za-z0-9_-]{43}$`,
+		},
+		{
+			name:    "should returns sha256",
+			cfg:     CFGGenerator{Type: TypeSHA256, Secret: "secret"},
+			pattern: `^[0-9a-f]{64}$`,
+		},
+		{
+			name:    "should returns error for sha256 without secret",
+			cfg:     CFGGenerator{Type: TypeSHA256},
+			wantErr: true,
+		},
+		{
+			name:    "should returns error for unknown type",
+			cfg:     CFGGenerator{Type: "md5"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := New(&tt.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			first, err := g.Generate()
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			second, err := g.Generate()
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			if !regexp.MustCompile(tt.pattern).MatchString(first) {
+				t.Errorf("Generate() = %v, want match %v", first, tt.pattern)
+			}
+			if first == second {
+				t.Errorf("Generate() returns the same value twice: %v", first)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/dolefir/refresh-hash/clock"
+	"github.com/dolefir/refresh-hash/logger"
+	"github.com/dolefir/refresh-hash/repository"
+	"github.com/dolefir/refresh-hash/task"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const (
+	defaultMultiplier = 2
+	defaultInterval   = 5 * time.Second
+)
+
+// Checker reports readiness of the application, it is ready
+// while every ticker keeps rotating its hash in time.
+type Checker struct {
+	tickers    task.Tickers
+	multiplier float64
+	clock      clock.Clock
+	log        logger.Logger
+	// repository is checked with the tickers, nil skips it.
+	repository repository.Pinger
+	// stopping is set once the application is shutting down.
+	stopping atomic.Bool
+}
+
+// Option options for Checker setup.
+type Option func(c *Checker)
+
+// WithRepository reports not ready while the repository ping fails.
+func WithRepository(repo repository.Pinger) Option {
+	return func(c *Checker) {
+		c.repository = repo
+	}
+}
+
+// NewChecker returns a new Checker, a ticker is stale once its last
+// rotation is older than multiplier rotation intervals, 2 by default.
+func NewChecker(tickers task.Tickers, multiplier float64, clk clock.Clock, log logger.Logger, options ...Option) *Checker {
+	if multiplier <= 0 {
+		multiplier = defaultMultiplier
+	}
+
+	c := &Checker{
+		tickers:    tickers,
+		multiplier: multiplier,
+		clock:      clk,
+		log:        log,
+	}
+	for _, option := range options {
+		option(c)
+	}
+
+	return c
+}
+
+// Ready returns nil when the application is ready to serve.
+func (c *Checker) Ready() error {
+	if c.stopping.Load() {
+		return errors.New("shutting down")
+	}
+
+	err := c.tickers.Check(c.clock.Now(), c.multiplier)
+	if c.repository != nil {
+		if pingErr := c.repository.Ping(); pingErr != nil {
+			err = errors.Join(err, fmt.Errorf("repository: %w", pingErr))
+		}
+	}
+
+	return err
+}
+
+// Shutdown reports the application not ready from now on, it is called
+// at the start of the shutdown to take the instance out of the balancer.
+func (c *Checker) Shutdown() {
+	c.stopping.Store(true)
+}
+
+// Watch keeps the overall status of the gRPC health server
+// in sync with the readiness until ctx is done.
+func (c *Checker) Watch(ctx context.Context, srv *health.Server, interval time.Duration) {
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	status := healthpb.HealthCheckResponse_UNKNOWN
+	for {
+		next := healthpb.HealthCheckResponse_SERVING
+		if err := c.Ready(); err != nil {
+			next = healthpb.HealthCheckResponse_NOT_SERVING
+			if status != next {
+				c.log.Warnf("health.Watch: not ready: %v", err)
+			}
+		}
+		if status != next {
+			srv.SetServingStatus("", next)
+			status = next
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dolefir/refresh-hash/clock"
+	"github.com/dolefir/refresh-hash/config"
+	"github.com/dolefir/refresh-hash/logger"
+	"github.com/dolefir/refresh-hash/task"
+)
+
+type pingerMock func() error
+
+func (m pingerMock) Ping() error {
+	return m()
+}
+
+func TestChecker_Ready(t *testing.T) {
+	const interval = time.Minute
+
+	cfg := config.NewConfig("")
+	log := logger.NewLogger((*logger.CFGLogger)(&cfg.Logger), nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	calls := make(chan struct{}, 1)
+	release := make(chan struct{})
+	clk := clock.NewFake(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+	ticker := task.NewRefreshTicker(interval, time.Minute, task.RefresherFunc(func(ctx context.Context) error {
+		select {
+		case calls <- struct{}{}:
+		default:
+		}
+		<-release
+		return nil
+	}), log, task.WithClock(clk))
+	checker := NewChecker(task.Tickers{"default": ticker}, 2, clk, log)
+
+	if err := checker.Ready(); err == nil {
+		t.Error("ready before the ticker runs")
+	}
+
+	done := make(chan error)
+	go func() {
+		done <- ticker.Start(ctx)
+	}()
+	clk.BlockUntil(1)
+	if err := checker.Ready(); err != nil {
+		t.Errorf("not ready after start: %v", err)
+	}
+
+	// The rotation hangs while the clock moves past two intervals.
+	clk.Advance(interval)
+	<-calls
+	clk.Advance(2 * interval)
+	if err := checker.Ready(); err == nil {
+		t.Error("ready with a stale rotation")
+	}
+
+	close(release)
+	deadline := time.Now().Add(time.Second)
+	for checker.Ready() != nil {
+		if time.Now().After(deadline) {
+			t.Fatalf("not ready after the rotation: %v", checker.Ready())
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestChecker_ReadyRepository(t *testing.T) {
+	tests := []struct {
+		name    string
+		ping    error
+		wantErr string
+	}{
+		{
+			name: "should be ready while the repository answers",
+		},
+		{
+			name:    "should not be ready while the repository ping fails",
+			ping:    errors.New("disk is gone"),
+			wantErr: "repository: disk is gone",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.NewConfig("")
+			log := logger.NewLogger((*logger.CFGLogger)(&cfg.Logger), nil)
+			checker := NewChecker(task.Tickers{}, 0, clock.NewFake(time.Now()), log,
+				WithRepository(pingerMock(func() error { return tt.ping })))
+
+			err := checker.Ready()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("wrong error %v, expected - %s", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestChecker_Shutdown(t *testing.T) {
+	cfg := config.NewConfig("")
+	log := logger.NewLogger((*logger.CFGLogger)(&cfg.Logger), nil)
+	checker := NewChecker(task.Tickers{}, 0, clock.NewFake(time.Now()), log)
+
+	if err := checker.Ready(); err != nil {
+		t.Fatalf("not ready before shutdown: %v", err)
+	}
+	checker.Shutdown()
+	if err := checker.Ready(); err == nil {
+		t.Error("ready after shutdown")
+	}
+}
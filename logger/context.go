@@ -0,0 +1,65 @@
+package logger
+
+import (
+	"context"
+
+	"github.com/dolefir/refresh-hash/requestid"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Log fields extracted from a context by the Ctx methods.
+const (
+	NamespaceField = "namespace"
+	TraceIDField   = "trace_id"
+	SpanIDField    = "span_id"
+)
+
+// ComponentField names the part of the application writing a log.
+const ComponentField = "component"
+
+type (
+	loggerKey    struct{}
+	namespaceKey struct{}
+)
+
+// WithContext returns a copy of ctx carrying the logger.
+func WithContext(ctx context.Context, log Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, log)
+}
+
+// FromContext returns the logger of ctx, fallback if there is none.
+func FromContext(ctx context.Context, fallback Logger) Logger {
+	if log, ok := ctx.Value(loggerKey{}).(Logger); ok {
+		return log
+	}
+
+	return fallback
+}
+
+// ContextWithNamespace returns a copy of ctx carrying the hash namespace.
+func ContextWithNamespace(ctx context.Context, namespace string) context.Context {
+	return context.WithValue(ctx, namespaceKey{}, namespace)
+}
+
+// NamespaceFromContext returns the hash namespace of ctx, false if there is none.
+func NamespaceFromContext(ctx context.Context) (string, bool) {
+	namespace, ok := ctx.Value(namespaceKey{}).(string)
+	return namespace, ok && namespace != ""
+}
+
+// contextFields returns the request ID, namespace and
+// trace and span IDs of ctx as key-value pairs.
+func contextFields(ctx context.Context) []interface{} {
+	var fields []interface{}
+	if id, ok := requestid.FromContext(ctx); ok {
+		fields = append(fields, requestid.LogField, id)
+	}
+	if namespace, ok := NamespaceFromContext(ctx); ok {
+		fields = append(fields, NamespaceField, namespace)
+	}
+	if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+		fields = append(fields, TraceIDField, span.TraceID().String(), SpanIDField, span.SpanID().String())
+	}
+
+	return fields
+}
@@ -0,0 +1,60 @@
+package logger
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/dolefir/refresh-hash/requestid"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func Test_contextFields(t *testing.T) {
+	span := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:  trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+	})
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want []interface{}
+	}{
+		{
+			name: "should return no fields",
+			ctx:  context.Background(),
+		},
+		{
+			name: "should return all fields",
+			ctx: trace.ContextWithSpanContext(
+				ContextWithNamespace(requestid.NewContext(context.Background(), "req-1"), "csrf"),
+				span,
+			),
+			want: []interface{}{
+				requestid.LogField, "req-1",
+				NamespaceField, "csrf",
+				TraceIDField, "4bf92f3577b34da6a3ce929d0e0e4736",
+				SpanIDField, "00f067aa0ba902b7",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := contextFields(tt.ctx); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("contextFields() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	fallback := NewLogger(&CFGLogger{}, nil)
+	if got := FromContext(context.Background(), fallback); got != fallback {
+		t.Error("expected fallback logger")
+	}
+
+	scoped := fallback.With("key", "value")
+	if got := FromContext(WithContext(context.Background(), scoped), fallback); got == fallback {
+		t.Error("expected logger of the context")
+	}
+}
@@ -0,0 +1,87 @@
+package logger
+
+import (
+	"sync"
+	"time"
+)
+
+// LevelState is the current log level.
+type LevelState struct {
+	Level string `json:"level"`
+	// RevertAt is when a temporary level is reverted, nil if it is permanent.
+	RevertAt *time.Time `json:"revert_at,omitempty"`
+}
+
+// LevelControl changes the log level at runtime, a temporary
+// level is reverted after its duration to the last permanent one.
+type LevelControl struct {
+	log    Logger
+	mu     sync.Mutex
+	base   string
+	revert *time.Timer
+	state  LevelState
+}
+
+// NewLevelControl returns a LevelControl of the logger
+// and all loggers derived from it.
+func NewLevelControl(log Logger) *LevelControl {
+	return &LevelControl{
+		log:   log,
+		base:  log.Level(),
+		state: LevelState{Level: log.Level()},
+	}
+}
+
+// Get returns the current log level.
+func (c *LevelControl) Get() LevelState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.state
+}
+
+// Set changes the log level, it is reverted after revertAfter
+// unless it is 0. A permanent level cancels a pending revert.
+func (c *LevelControl) Set(level string, revertAfter time.Duration) (LevelState, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.log.SetLevel(level); err != nil {
+		return c.state, err
+	}
+	if c.revert != nil {
+		c.revert.Stop()
+		c.revert = nil
+	}
+
+	c.state = LevelState{Level: level}
+	if revertAfter <= 0 {
+		c.base = level
+		c.log.Infof("log level set to %s", level)
+		return c.state, nil
+	}
+
+	revertAt := time.Now().Add(revertAfter)
+	c.state.RevertAt = &revertAt
+	var timer *time.Timer
+	timer = time.AfterFunc(revertAfter, func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		// A timer stopped too late must not revert a newer level.
+		if c.revert != timer {
+			return
+		}
+		c.revert = nil
+		if err := c.log.SetLevel(c.base); err != nil {
+			c.log.Errorf("revert log level: %v", err)
+			return
+		}
+		c.state = LevelState{Level: c.base}
+		c.log.Infof("log level reverted to %s", c.base)
+	})
+	c.revert = timer
+	c.log.Infof("log level set to %s until %s", level, revertAt.Format(time.RFC3339))
+
+	return c.state, nil
+}
@@ -0,0 +1,49 @@
+package logger
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLevelControl_Set(t *testing.T) {
+	log := NewLogger(&CFGLogger{LogLevel: infoLvl}, nil)
+	control := NewLevelControl(log)
+
+	if _, err := control.Set("verbose", 0); err == nil {
+		t.Error("expected error of unknown level")
+	}
+
+	state, err := control.Set(debugLvl, 20*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Level != debugLvl || state.RevertAt == nil {
+		t.Errorf("wrong state %+v, expected temporary debug level", state)
+	}
+	if level := log.With("key", "value").Level(); level != debugLvl {
+		t.Errorf("wrong level of derived logger (%s), expected - %s", level, debugLvl)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for control.Get().Level != infoLvl {
+		if time.Now().After(deadline) {
+			t.Fatalf("level %s is not reverted", control.Get().Level)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if log.Level() != infoLvl || control.Get().RevertAt != nil {
+		t.Errorf("wrong state after revert %+v", control.Get())
+	}
+
+	// A permanent level cancels a pending revert.
+	if _, err := control.Set(debugLvl, 20*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := control.Set(errorLvl, 0); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if log.Level() != errorLvl {
+		t.Errorf("wrong level (%s), expected - %s", log.Level(), errorLvl)
+	}
+}
@@ -0,0 +1,287 @@
+package logger
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backupTimeFormat is the time suffix of rotated files,
+// it sorts lexically in time order.
+const backupTimeFormat = "20060102T150405.000"
+
+// RotateOptions defines when a log file is rotated and how many backups are kept.
+type RotateOptions struct {
+	// MaxSize rotates the file before it grows over MaxSize bytes, 0 disables.
+	MaxSize int64
+	// MaxAge rotates the file once it is older than MaxAge, 0 disables.
+	MaxAge time.Duration
+	// MaxBackups is the number of kept backups, 0 keeps all.
+	MaxBackups int
+	// Compress gzips backups.
+	Compress bool
+}
+
+// RotatingFile is a log file rotated by size and age, a rotated
+// file is renamed to name-<time>.ext and optionally compressed.
+type RotatingFile struct {
+	path string
+	opts RotateOptions
+	now  func() time.Time
+
+	mu   sync.Mutex
+	file *os.File
+	// closed is set by Close, a nil file of an open
+	// RotatingFile is reopened on the next write.
+	closed bool
+	size   int64
+	// startedAt is the age origin of the file. A non-empty file
+	// was started by the newest rotation, so its age is taken from
+	// the newest backup and a restart does not reset it.
+	startedAt time.Time
+
+	// mill serializes compression and removal of backups.
+	mill sync.Mutex
+	wg   sync.WaitGroup
+}
+
+// NewRotatingFile opens the log file for appending, creating it and its directory.
+func NewRotatingFile(path string, opts RotateOptions) (*RotatingFile, error) {
+	r := &RotatingFile{
+		path: path,
+		opts: opts,
+		now:  time.Now,
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Write writes p to the file, rotating it first when p would
+// exceed the max size or the file is older than the max age.
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return 0, os.ErrClosed
+	}
+	if r.file == nil {
+		// A previous rotation failed to reopen the file.
+		if err := r.open(); err != nil {
+			return 0, err
+		}
+	}
+	tooBig := r.opts.MaxSize > 0 && r.size > 0 && r.size+int64(len(p)) > r.opts.MaxSize
+	tooOld := r.opts.MaxAge > 0 && r.now().Sub(r.startedAt) >= r.opts.MaxAge
+	if tooBig || tooOld {
+		if err := r.rotate(); err != nil {
+			// Keep logging to the current file, a later write retries the rotation.
+			fmt.Fprintf(os.Stderr, "logger: rotate %s: %v\n", r.path, err)
+			if r.file == nil {
+				return 0, err
+			}
+		}
+	}
+
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+
+	return n, err
+}
+
+// Sync commits the file to the disk.
+func (r *RotatingFile) Sync() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return nil
+	}
+
+	return r.file.Sync()
+}
+
+// Close closes the file and waits for pending backups processing.
+func (r *RotatingFile) Close() error {
+	r.mu.Lock()
+	r.closed = true
+	var err error
+	if r.file != nil {
+		err = r.file.Close()
+		r.file = nil
+	}
+	r.mu.Unlock()
+	r.wg.Wait()
+
+	return err
+}
+
+func (r *RotatingFile) open() error {
+	file, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	r.file = file
+	r.size = info.Size()
+	r.startedAt = r.now()
+	if r.size > 0 {
+		// The file was never rotated without backups,
+		// its age is then counted from now.
+		if rotatedAt, ok := r.lastRotation(); ok {
+			r.startedAt = rotatedAt
+		}
+	}
+
+	return nil
+}
+
+// lastRotation returns the time of the newest backup.
+func (r *RotatingFile) lastRotation() (time.Time, bool) {
+	backups, err := r.backups()
+	if err != nil || len(backups) == 0 {
+		return time.Time{}, false
+	}
+
+	return r.backupTime(backups[len(backups)-1])
+}
+
+// rotate renames the current file to a backup and opens a new one,
+// the original file is reopened when the rename fails.
+// The caller holds the lock.
+func (r *RotatingFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return err
+	}
+	r.file = nil
+
+	prefix, ext := r.backupName()
+	backup := prefix + r.now().UTC().Format(backupTimeFormat) + ext
+	if err := os.Rename(r.path, backup); err != nil {
+		return errors.Join(err, r.open())
+	}
+	if err := r.open(); err != nil {
+		return err
+	}
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		if err := r.millBackups(backup); err != nil {
+			fmt.Fprintf(os.Stderr, "logger: rotate %s: %v\n", r.path, err)
+		}
+	}()
+
+	return nil
+}
+
+// backupName returns the prefix and extension of backups, app.log has app- and .log.
+func (r *RotatingFile) backupName() (string, string) {
+	ext := filepath.Ext(r.path)
+	return strings.TrimSuffix(r.path, ext) + "-", ext
+}
+
+// millBackups compresses the new backup and removes ones over MaxBackups.
+func (r *RotatingFile) millBackups(backup string) error {
+	r.mill.Lock()
+	defer r.mill.Unlock()
+
+	if r.opts.Compress {
+		if err := compress(backup); err != nil {
+			return err
+		}
+	}
+	if r.opts.MaxBackups <= 0 {
+		return nil
+	}
+
+	backups, err := r.backups()
+	if err != nil {
+		return err
+	}
+	for len(backups) > r.opts.MaxBackups {
+		if err := os.Remove(backups[0]); err != nil {
+			return err
+		}
+		backups = backups[1:]
+	}
+
+	return nil
+}
+
+// backups returns backup files, oldest first.
+func (r *RotatingFile) backups() ([]string, error) {
+	prefix, ext := r.backupName()
+	matches, err := filepath.Glob(prefix + "*" + ext + "*")
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []string
+	for _, match := range matches {
+		if _, ok := r.backupTime(match); ok {
+			backups = append(backups, match)
+		}
+	}
+	sort.Strings(backups)
+
+	return backups, nil
+}
+
+// backupTime parses the rotation time of a backup name.
+func (r *RotatingFile) backupTime(name string) (time.Time, bool) {
+	prefix, ext := r.backupName()
+	stamp := strings.TrimSuffix(strings.TrimSuffix(strings.TrimPrefix(name, prefix), ".gz"), ext)
+	t, err := time.Parse(backupTimeFormat, stamp)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return t, true
+}
+
+// compress replaces the file with its gzip.
+func compress(name string) error {
+	src, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(name+".gz", os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(dst)
+	if _, err := io.Copy(gz, src); err != nil {
+		dst.Close()
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		dst.Close()
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		return err
+	}
+
+	return os.Remove(name)
+}
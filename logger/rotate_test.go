@@ -0,0 +1,211 @@
+package logger
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRotatingFile_Write(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        RotateOptions
+		step        time.Duration
+		wantBackups int
+	}{
+		{
+			name:        "should rotate by size and keep max backups",
+			opts:        RotateOptions{MaxSize: 10, MaxBackups: 2},
+			step:        time.Millisecond,
+			wantBackups: 2,
+		},
+		{
+			name:        "should rotate by age and compress",
+			opts:        RotateOptions{MaxAge: time.Hour, Compress: true},
+			step:        time.Hour,
+			wantBackups: 3,
+		},
+		{
+			name: "should not rotate",
+			step: time.Hour,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "logs", "app.log")
+			now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+			file, err := NewRotatingFile(path, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			file.now = func() time.Time { return now }
+			file.startedAt = now
+
+			for _, line := range []string{"line-1\n", "line-2\n", "line-3\n", "line-4\n"} {
+				if _, err := file.Write([]byte(line)); err != nil {
+					t.Fatal(err)
+				}
+				now = now.Add(tt.step)
+			}
+			if err := file.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			backups, err := file.backups()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(backups) != tt.wantBackups {
+				t.Fatalf("wrong number of backups (%d), expected - %d: %v", len(backups), tt.wantBackups, backups)
+			}
+			for _, backup := range backups {
+				if strings.HasSuffix(backup, ".gz") != tt.opts.Compress {
+					t.Errorf("wrong compression of backup %s", backup)
+				}
+			}
+
+			current, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantBackups > 0 && string(current) != "line-4\n" {
+				t.Errorf("wrong current file %q", current)
+			}
+			if tt.opts.Compress {
+				if got := gunzip(t, backups[len(backups)-1]); got != "line-3\n" {
+					t.Errorf("wrong last backup %q", got)
+				}
+			}
+		})
+	}
+}
+
+func TestRotatingFile_WriteRenameFailed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	file, err := NewRotatingFile(path, RotateOptions{MaxSize: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.now = func() time.Time { return now }
+
+	// A non-empty directory in place of the backup fails the rename.
+	prefix, ext := file.backupName()
+	blocker := prefix + now.Format(backupTimeFormat) + ext
+	if err := os.MkdirAll(filepath.Join(blocker, "keep"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, line := range []string{"line-1\n", "line-2\n"} {
+		if _, err := file.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.RemoveAll(blocker); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(time.Second)
+	if _, err := file.Write([]byte("line-3\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := file.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	current, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(current) != "line-3\n" {
+		t.Errorf("wrong current file %q", current)
+	}
+	backups, err := file.backups()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 1 {
+		t.Fatalf("wrong number of backups (%d), expected - 1: %v", len(backups), backups)
+	}
+	if data, _ := os.ReadFile(backups[0]); string(data) != "line-1\nline-2\n" {
+		t.Errorf("wrong backup %q", data)
+	}
+}
+
+func TestRotatingFile_OpenAge(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name       string
+		backupAt   *time.Time
+		wantRotate bool
+	}{
+		{
+			name:       "should count the age from the newest backup",
+			backupAt:   func() *time.Time { at := now.Add(-2 * time.Hour); return &at }(),
+			wantRotate: true,
+		},
+		{
+			name: "should count the age from now without backups",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "app.log")
+			if err := os.WriteFile(path, []byte("line-1\n"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if tt.backupAt != nil {
+				backup := strings.TrimSuffix(path, ".log") + "-" + tt.backupAt.Format(backupTimeFormat) + ".log"
+				if err := os.WriteFile(backup, []byte("line-0\n"), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			file, err := NewRotatingFile(path, RotateOptions{MaxAge: time.Hour})
+			if err != nil {
+				t.Fatal(err)
+			}
+			file.now = func() time.Time { return now }
+			if _, err := file.Write([]byte("line-2\n")); err != nil {
+				t.Fatal(err)
+			}
+			if err := file.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			current, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if rotated := string(current) == "line-2\n"; rotated != tt.wantRotate {
+				t.Errorf("rotated is %v, expected - %v, current file %q", rotated, tt.wantRotate, current)
+			}
+		})
+	}
+}
+
+func gunzip(t *testing.T, name string) string {
+	t.Helper()
+
+	f, err := os.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(gz)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(data)
+}
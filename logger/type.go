@@ -1,5 +1,7 @@
 package logger
 
+import "context"
+
 // Logger common logger interface.
 type Logger interface {
 	// Info writes a information message.
@@ -26,6 +28,14 @@ type Logger interface {
 	Debugf(template string, args ...interface{})
 	// Debugw writes a formatted information message with key-value pairs.
 	Debugw(template string, args ...interface{})
+	// DebugfCtx writes a formatted debug message with fields of ctx.
+	DebugfCtx(ctx context.Context, template string, args ...interface{})
+	// InfofCtx writes a formatted information message with fields of ctx.
+	InfofCtx(ctx context.Context, template string, args ...interface{})
+	// WarnfCtx writes a formatted warning message with fields of ctx.
+	WarnfCtx(ctx context.Context, template string, args ...interface{})
+	// ErrorfCtx writes a formatted error message with fields of ctx.
+	ErrorfCtx(ctx context.Context, template string, args ...interface{})
 	// Fatal writes a fatal message.
 	Fatal(args ...interface{})
 	// Fatalf writes a formatted fatal message.
@@ -34,4 +44,11 @@ type Logger interface {
 	With(fields ...interface{}) Logger
 	// Flush any buffered log entries.
 	Flush() error
+	// Close flushes buffered log entries and closes the log file,
+	// the logger must not be used after.
+	Close() error
+	// Level returns the current log level.
+	Level() string
+	// SetLevel changes the log level of the logger and all loggers derived from it.
+	SetLevel(level string) error
 }
@@ -1,9 +1,12 @@
 package logger
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"time"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -28,6 +31,7 @@ const (
 
 	infoLvl  = "info"
 	debugLvl = "debug"
+	warnLvl  = "warn"
 	errorLvl = "error"
 )
 
@@ -95,52 +99,91 @@ func Output(o io.Writer) Option {
 
 type zapLog struct {
 	log *zap.SugaredLogger
+	// file is the log file shared by all loggers derived by With, nil without one.
+	file io.Closer
+	// level is shared by all loggers derived by With.
+	level zap.AtomicLevel
 }
 
 // LoggerEnv ...
 func LoggerEnv(mode, format int, options ...Option) Logger {
+	return newZapLog(mode, format, DebugLevel, nil, options...)
+}
+
+// fileSink is a file output next to the console.
+type fileSink struct {
+	writer zapcore.WriteSyncer
+	// level is the fixed level of the file,
+	// nil follows the console level.
+	level zapcore.LevelEnabler
+}
+
+func newZapLog(mode, format int, level zapcore.Level, file *fileSink, options ...Option) zapLog {
+	atomicLevel := zap.NewAtomicLevelAt(level)
+	core := zapcore.NewCore(
+		newEncoder(mode, format, true),
+		zapcore.AddSync(os.Stdout),
+		atomicLevel,
+	)
+	if file != nil {
+		var fileLevel zapcore.LevelEnabler = atomicLevel
+		if file.level != nil {
+			fileLevel = file.level
+		}
+		core = zapcore.NewTee(core, zapcore.NewCore(
+			newEncoder(mode, format, false),
+			file.writer,
+			fileLevel,
+		))
+	}
+
+	log := zap.New(core)
+	log = log.WithOptions(zap.AddCallerSkip(1))
+
+	if len(options) > 0 {
+		for _, option := range options {
+			log = option(log)
+		}
+	}
+
+	z := zapLog{log: log.Sugar(), level: atomicLevel}
+	if file != nil {
+		if closer, ok := file.writer.(io.Closer); ok {
+			z.file = closer
+		}
+	}
+
+	return z
+}
+
+// newEncoder returns the encoder of the mode and format,
+// color levels are meant for a terminal only.
+func newEncoder(mode, format int, color bool) zapcore.Encoder {
 	var cfg zapcore.EncoderConfig
-	var enc zapcore.Encoder
 
 	switch mode {
 	case ModDev:
 		cfg = zap.NewDevelopmentEncoderConfig()
-		cfg.EncodeLevel = zapcore.CapitalColorLevelEncoder
 	case ModProd:
 		cfg = zap.NewProductionEncoderConfig()
-		cfg.EncodeLevel = zapcore.CapitalColorLevelEncoder
 	default:
 		cfg = zap.NewDevelopmentEncoderConfig()
-		cfg.EncodeLevel = zapcore.CapitalColorLevelEncoder
 	}
 
+	cfg.EncodeLevel = zapcore.CapitalLevelEncoder
+	if color {
+		cfg.EncodeLevel = zapcore.CapitalColorLevelEncoder
+	}
 	cfg.FunctionKey = "F"
 
 	switch format {
 	case FormatJSON:
-		enc = zapcore.NewJSONEncoder(cfg)
+		return zapcore.NewJSONEncoder(cfg)
 	case FormatConsole:
-		enc = zapcore.NewConsoleEncoder(cfg)
+		return zapcore.NewConsoleEncoder(cfg)
 	default:
-		enc = zapcore.NewJSONEncoder(cfg)
-	}
-
-	coreJSON := zapcore.NewCore(
-		enc,
-		zapcore.AddSync(os.Stdout),
-		zap.DebugLevel,
-	)
-
-	log := zap.New(coreJSON)
-	log = log.WithOptions(zap.AddCallerSkip(1))
-
-	if len(options) > 0 {
-		for _, option := range options {
-			log = option(log)
-		}
+		return zapcore.NewJSONEncoder(cfg)
 	}
-
-	return zapLog{log: log.Sugar()}
 }
 
 type CFGLogger struct {
@@ -148,8 +191,20 @@ type CFGLogger struct {
 	Mode string
 	// format mode text/json
 	LogFormat string
-	// log level debug/error
+	// log level debug/info/warn/error
 	LogLevel string
+	// log file path, empty logs to the console only
+	File string
+	// log level of the file, empty follows the console level
+	FileLevel string
+	// max file size in megabytes before rotation, 0 disables
+	FileMaxSize int
+	// max age of the file before rotation, 0 disables
+	FileMaxAge time.Duration
+	// number of kept rotated files, 0 keeps all
+	FileMaxBackups int
+	// gzip rotated files
+	FileCompress bool
 }
 
 func logMod(mode string) int {
@@ -192,6 +247,8 @@ func logLeven(level string) int {
 		l = int(InfoLevel)
 	case debugLvl:
 		l = int(DebugLevel)
+	case warnLvl:
+		l = int(WarnLevel)
 	case errorLvl:
 		l = int(ErrorLevel)
 	default:
@@ -202,15 +259,72 @@ func logLeven(level string) int {
 	return l
 }
 
+// NewLogger returns a console logger, cfg.File is opened by OpenLogger.
 func NewLogger(cfg *CFGLogger, tags map[string]string) Logger {
-	return LoggerEnv(
+	return newZapLog(
 		logMod(cfg.Mode),
 		logFormat(cfg.LogFormat),
+		zapcore.Level(logLeven(cfg.LogLevel)),
+		nil,
 		Tags(tags),
-		SelectLevel(logLeven(cfg.LogLevel)),
 	)
 }
 
+// OpenLogger returns a logger writing to the console and to cfg.File
+// when it is set, it fails if the file cannot be opened.
+func OpenLogger(cfg *CFGLogger, tags map[string]string) (Logger, error) {
+	var file *fileSink
+	if cfg.File != "" {
+		var err error
+		if file, err = logFile(cfg); err != nil {
+			return nil, fmt.Errorf("open log file: %w", err)
+		}
+	}
+
+	return newZapLog(
+		logMod(cfg.Mode),
+		logFormat(cfg.LogFormat),
+		zapcore.Level(logLeven(cfg.LogLevel)),
+		file,
+		Tags(tags),
+	), nil
+}
+
+func logFile(cfg *CFGLogger) (*fileSink, error) {
+	file, err := NewRotatingFile(cfg.File, RotateOptions{
+		MaxSize:    int64(cfg.FileMaxSize) << 20,
+		MaxAge:     cfg.FileMaxAge,
+		MaxBackups: cfg.FileMaxBackups,
+		Compress:   cfg.FileCompress,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sink := &fileSink{writer: file}
+	if cfg.FileLevel != "" {
+		sink.level = zapcore.Level(logLeven(cfg.FileLevel))
+	}
+
+	return sink, nil
+}
+
+// ParseLevel returns the level of a debug/info/warn/error name.
+func ParseLevel(level string) (zapcore.Level, error) {
+	switch level {
+	case debugLvl:
+		return DebugLevel, nil
+	case infoLvl:
+		return InfoLevel, nil
+	case warnLvl:
+		return WarnLevel, nil
+	case errorLvl:
+		return ErrorLevel, nil
+	default:
+		return DebugLevel, fmt.Errorf("unknown log level %q", level)
+	}
+}
+
 // Info writes a information message.
 func (z zapLog) Info(args ...interface{}) {
 	z.log.Info(args...)
@@ -271,6 +385,44 @@ func (z zapLog) Debugw(template string, args ...interface{}) {
 	z.log.Debugw(template, args...)
 }
 
+// DebugfCtx writes a formatted debug message with fields of ctx.
+func (z zapLog) DebugfCtx(ctx context.Context, template string, args ...interface{}) {
+	if log := z.withContext(ctx, DebugLevel); log != nil {
+		log.Debugf(template, args...)
+	}
+}
+
+// InfofCtx writes a formatted information message with fields of ctx.
+func (z zapLog) InfofCtx(ctx context.Context, template string, args ...interface{}) {
+	if log := z.withContext(ctx, InfoLevel); log != nil {
+		log.Infof(template, args...)
+	}
+}
+
+// WarnfCtx writes a formatted warning message with fields of ctx.
+func (z zapLog) WarnfCtx(ctx context.Context, template string, args ...interface{}) {
+	if log := z.withContext(ctx, WarnLevel); log != nil {
+		log.Warnf(template, args...)
+	}
+}
+
+// ErrorfCtx writes a formatted error message with fields of ctx.
+func (z zapLog) ErrorfCtx(ctx context.Context, template string, args ...interface{}) {
+	if log := z.withContext(ctx, ErrorLevel); log != nil {
+		log.Errorf(template, args...)
+	}
+}
+
+// withContext returns the logger with fields of ctx,
+// nil if the level is disabled to skip extracting them.
+func (z zapLog) withContext(ctx context.Context, level zapcore.Level) *zap.SugaredLogger {
+	if !z.log.Desugar().Core().Enabled(level) {
+		return nil
+	}
+
+	return z.log.With(contextFields(ctx)...)
+}
+
 // Fatal writes a fatal message.
 func (z zapLog) Fatal(args ...interface{}) {
 	z.log.Fatal(args...)
@@ -283,10 +435,36 @@ func (z zapLog) Fatalf(template string, args ...interface{}) {
 
 // With add fields to be used for all logs
 func (z zapLog) With(fields ...interface{}) Logger {
-	return zapLog{log: z.log.With(fields...)}
+	return zapLog{log: z.log.With(fields...), level: z.level, file: z.file}
+}
+
+// Level returns the current log level.
+func (z zapLog) Level() string {
+	return z.level.Level().String()
+}
+
+// SetLevel changes the log level of the logger and all loggers derived from it.
+func (z zapLog) SetLevel(level string) error {
+	l, err := ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	z.level.SetLevel(l)
+
+	return nil
 }
 
 // Flush any buffered log entries
 func (z zapLog) Flush() error {
 	return z.log.Sync()
 }
+
+// Close flushes buffered log entries and closes the log file.
+func (z zapLog) Close() error {
+	err := z.log.Sync()
+	if z.file != nil {
+		err = errors.Join(err, z.file.Close())
+	}
+
+	return err
+}
@@ -0,0 +1,61 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestOpenLogger(t *testing.T) {
+	dir := t.TempDir()
+	// A regular file in place of the log directory fails the open.
+	blocker := filepath.Join(dir, "blocker")
+	if err := os.WriteFile(blocker, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		file    string
+		wantErr bool
+	}{
+		{
+			name: "should log to the console only",
+		},
+		{
+			name: "should log to the file",
+			file: filepath.Join(dir, "logs", "app.log"),
+		},
+		{
+			name:    "should fail when the file cannot be opened",
+			file:    filepath.Join(blocker, "app.log"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log, err := OpenLogger(&CFGLogger{LogLevel: infoLvl, File: tt.file}, nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("OpenLogger() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			log.Info("hello")
+			// Syncing stdout fails when it is a pipe, the file is closed anyway.
+			_ = log.Close()
+			if tt.file == "" {
+				return
+			}
+			data, err := os.ReadFile(tt.file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(data), "hello") {
+				t.Errorf("message not in the file %q", data)
+			}
+		})
+	}
+}
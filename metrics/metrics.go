@@ -0,0 +1,121 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "refresh_hash"
+
+// Metrics holds Prometheus collectors of the application.
+type Metrics struct {
+	registry        *prometheus.Registry
+	rotations       *prometheus.CounterVec
+	lastRotation    *prometheus.GaugeVec
+	refreshFailures *prometheus.CounterVec
+	refreshDuration *prometheus.HistogramVec
+	httpRequests    *prometheus.CounterVec
+	httpDuration    *prometheus.HistogramVec
+	grpcRequests    *prometheus.CounterVec
+	grpcDuration    *prometheus.HistogramVec
+}
+
+// New returns Metrics registered in a dedicated registry
+// together with the Go runtime and process collectors.
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		rotations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "rotations_total",
+			Help:      "Number of successful hash rotations.",
+		}, []string{"hash_namespace"}),
+		lastRotation: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "last_rotation_timestamp_seconds",
+			Help:      "Unix time of the last successful hash rotation.",
+		}, []string{"hash_namespace"}),
+		refreshFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "refresh_failures_total",
+			Help:      "Number of failed hash refreshes.",
+		}, []string{"hash_namespace"}),
+		refreshDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "refresh_duration_seconds",
+			Help:      "Duration of hash refreshes.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"hash_namespace"}),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "Number of REST API requests.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Latency of REST API requests.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		grpcRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "grpc_requests_total",
+			Help:      "Number of gRPC requests.",
+		}, []string{"method", "code"}),
+		grpcDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "grpc_request_duration_seconds",
+			Help:      "Latency of gRPC requests.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.rotations,
+		m.lastRotation,
+		m.refreshFailures,
+		m.refreshDuration,
+		m.httpRequests,
+		m.httpDuration,
+		m.grpcRequests,
+		m.grpcDuration,
+	)
+
+	return m
+}
+
+// Handler returns the HTTP handler exposing the metrics.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// ObserveRefresh records a hash refresh of the namespace.
+func (m *Metrics) ObserveRefresh(hashNamespace string, duration time.Duration, err error) {
+	m.refreshDuration.WithLabelValues(hashNamespace).Observe(duration.Seconds())
+	if err != nil {
+		m.refreshFailures.WithLabelValues(hashNamespace).Inc()
+		return
+	}
+
+	m.rotations.WithLabelValues(hashNamespace).Inc()
+	m.lastRotation.WithLabelValues(hashNamespace).SetToCurrentTime()
+}
+
+// ObserveHTTP records a REST API request, route is the route template.
+func (m *Metrics) ObserveHTTP(method, route string, status int, duration time.Duration) {
+	m.httpRequests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+	m.httpDuration.WithLabelValues(method, route).Observe(duration.Seconds())
+}
+
+// ObserveGRPC records a gRPC request.
+func (m *Metrics) ObserveGRPC(method, code string, duration time.Duration) {
+	m.grpcRequests.WithLabelValues(method, code).Inc()
+	m.grpcDuration.WithLabelValues(method).Observe(duration.Seconds())
+}
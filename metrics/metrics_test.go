@@ -0,0 +1,30 @@
+package metrics
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetrics_ObserveRefresh(t *testing.T) {
+	m := New()
+
+	m.ObserveRefresh("default", time.Millisecond, nil)
+	m.ObserveRefresh("default", time.Millisecond, nil)
+	m.ObserveRefresh("default", time.Millisecond, errors.New("error"))
+
+	if got := testutil.ToFloat64(m.rotations.WithLabelValues("default")); got != 2 {
+		t.Errorf("rotations = %v, want 2", got)
+	}
+	if got := testutil.ToFloat64(m.refreshFailures.WithLabelValues("default")); got != 1 {
+		t.Errorf("refresh failures = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(m.lastRotation.WithLabelValues("default")); got == 0 {
+		t.Error("last rotation timestamp is not set")
+	}
+	if got := testutil.CollectAndCount(m.refreshDuration); got != 1 {
+		t.Errorf("refresh duration series = %v, want 1", got)
+	}
+}
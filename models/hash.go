@@ -2,8 +2,40 @@ package models
 
 import "time"
 
+// DefaultNamespace is the namespace of the global hash.
+const DefaultNamespace = "default"
+
+// ReservedNamespaces are not allowed as namespace names, the default
+// namespace and the REST routes of /api/hash/<name> would clash.
+var ReservedNamespaces = []string{DefaultNamespace, "history", "stream", "refresh", "validate"}
+
+// Hash states returned by the validation.
+const (
+	HashStateCurrent = "current"
+	HashStateGrace   = "grace"
+	HashStateInvalid = "invalid"
+)
+
 // Hash defines a data model.
 type Hash struct {
 	ID       string    `json:"uuid"`
 	Datatime time.Time `json:"datatime"`
+	// Generator is the type of generator produced the hash.
+	Generator string `json:"generator,omitempty"`
+	// Namespace the hash belongs to.
+	Namespace string `json:"namespace,omitempty"`
+	// ExpiresAt is the next scheduled rotation,
+	// it is set for the current hash only.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	// TTL is the number of seconds until ExpiresAt,
+	// it is set together with ExpiresAt and may be 0.
+	TTL *int64 `json:"ttl,omitempty"`
+}
+
+// Validation defines a result of the hash validation.
+type Validation struct {
+	Valid bool   `json:"valid"`
+	State string `json:"state"`
+	// ValidUntil is set for a hash in the grace period.
+	ValidUntil *time.Time `json:"valid_until,omitempty"`
 }
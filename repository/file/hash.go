@@ -0,0 +1,172 @@
+package file
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/dolefir/refresh-hash/models"
+	"github.com/dolefir/refresh-hash/repository"
+)
+
+// Repository holds methods for works with hash data
+// persisted in a snapshot file.
+type Repository struct {
+	path       string
+	namespaces map[string][]models.Hash
+	retention  repository.Retention
+	*sync.RWMutex
+}
+
+// snapshot is the on-disk format, hashes
+// of every namespace, newest first.
+type snapshot struct {
+	Namespaces map[string][]models.Hash `json:"namespaces"`
+}
+
+// NewRepository returns new hash Repository and restores
+// the last saved hash from the snapshot file if it exists.
+func NewRepository(path string, retention repository.Retention) (*Repository, error) {
+	if path == "" {
+		return nil, errors.New("file repository: empty snapshot path")
+	}
+
+	r := &Repository{
+		path:       path,
+		namespaces: make(map[string][]models.Hash),
+		retention:  retention,
+		RWMutex:    new(sync.RWMutex),
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("file repository: create dir: %w", err)
+	}
+
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return r, nil
+	case err != nil:
+		return nil, fmt.Errorf("file repository: read snapshot: %w", err)
+	}
+
+	var snap snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return nil, fmt.Errorf("file repository: decode snapshot: %w", err)
+	}
+	for name, history := range snap.Namespaces {
+		r.namespaces[name] = history
+	}
+
+	return r, nil
+}
+
+// Set the information record.
+// The snapshot is written to a temporary file first and then
+// renamed over the old one, so a crash never leaves a torn file.
+func (r *Repository) Set(h *models.Hash) error {
+	r.RWMutex.Lock()
+	defer r.RWMutex.Unlock()
+
+	hash := models.Hash{ID: h.ID, Datatime: h.Datatime, Generator: h.Generator, Namespace: h.Namespace}
+	history := r.retention.Apply(append([]models.Hash{hash}, r.namespaces[hash.Namespace]...), hash.Datatime)
+
+	namespaces := make(map[string][]models.Hash, len(r.namespaces)+1)
+	for name, h := range r.namespaces {
+		namespaces[name] = h
+	}
+	namespaces[hash.Namespace] = history
+
+	if err := r.save(namespaces); err != nil {
+		return err
+	}
+	r.namespaces = namespaces
+
+	return nil
+}
+
+// Get the read information.
+func (r *Repository) Get(namespace string) (*models.Hash, error) {
+	r.RWMutex.RLock()
+	defer r.RWMutex.RUnlock()
+
+	hash := models.Hash{Namespace: namespace}
+	if history := r.namespaces[namespace]; len(history) > 0 {
+		hash = history[0]
+	}
+
+	return &hash, nil
+}
+
+// List returns retained hashes of the namespace, newest first.
+func (r *Repository) List(namespace string) ([]models.Hash, error) {
+	r.RWMutex.RLock()
+	defer r.RWMutex.RUnlock()
+
+	history := make([]models.Hash, len(r.namespaces[namespace]))
+	copy(history, r.namespaces[namespace])
+
+	return history, nil
+}
+
+// Ping checks the snapshot directory still accepts new files,
+// which every Set needs.
+func (r *Repository) Ping() error {
+	tmp, err := os.CreateTemp(filepath.Dir(r.path), filepath.Base(r.path)+".ping-*")
+	if err != nil {
+		return fmt.Errorf("file repository: ping: %w", err)
+	}
+	tmp.Close()
+
+	return os.Remove(tmp.Name())
+}
+
+func (r *Repository) save(namespaces map[string][]models.Hash) error {
+	data, err := json.Marshal(snapshot{Namespaces: namespaces})
+	if err != nil {
+		return fmt.Errorf("file repository: encode snapshot: %w", err)
+	}
+
+	dir := filepath.Dir(r.path)
+	tmp, err := os.CreateTemp(dir, filepath.Base(r.path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("file repository: create temp file: %w", err)
+	}
+	// Cleanup is a no-op once the rename succeeded.
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("file repository: write snapshot: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("file repository: sync snapshot: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("file repository: close snapshot: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), r.path); err != nil {
+		return fmt.Errorf("file repository: rename snapshot: %w", err)
+	}
+
+	return syncDir(dir)
+}
+
+// syncDir flushes the directory entry so the rename survives a power loss.
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return fmt.Errorf("file repository: open dir: %w", err)
+	}
+	defer d.Close()
+
+	if err := d.Sync(); err != nil {
+		return fmt.Errorf("file repository: sync dir: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,116 @@
+package file
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dolefir/refresh-hash/models"
+	"github.com/dolefir/refresh-hash/repository"
+)
+
+func TestRepository_SetAndRestore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", "hash.json")
+	want := &models.Hash{
+		ID:        "996f2357-31af-4b1a-9889-a075be3de0a9",
+		Datatime:  time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Namespace: "csrf",
+	}
+
+	r, err := NewRepository(path, repository.Retention{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Set(want); err != nil {
+		t.Fatalf("Repository.Set() error = %v", err)
+	}
+
+	// A new repository over the same file simulates a restart.
+	restored, err := NewRepository(path, repository.Retention{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := restored.Get("csrf")
+	if err != nil {
+		t.Fatalf("Repository.Get() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Repository.Get() = %v, want %v", got, want)
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected only the snapshot file, got %d entries", len(entries))
+	}
+}
+
+func TestRepository_Empty(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		data    string
+		wantErr bool
+	}{
+		{
+			name: "should returns empty hash without snapshot",
+			path: filepath.Join(t.TempDir(), "hash.json"),
+		},
+		{
+			name:    "should returns error on corrupted snapshot",
+			path:    filepath.Join(t.TempDir(), "hash.json"),
+			data:    "{",
+			wantErr: true,
+		},
+		{
+			name:    "should returns error on empty path",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.data != "" {
+				if err := os.WriteFile(tt.path, []byte(tt.data), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			r, err := NewRepository(tt.path, repository.Retention{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewRepository() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			got, _ := r.Get(models.DefaultNamespace)
+			if got.ID != "" {
+				t.Errorf("Repository.Get() = %v, want empty hash", got)
+			}
+		})
+	}
+}
+
+func TestRepository_Ping(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "data")
+	repo, err := NewRepository(filepath.Join(dir, "hash.json"), repository.Retention{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repo.Ping(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("ping left files %v", entries)
+	}
+
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Ping(); err == nil {
+		t.Error("expected error of a removed directory")
+	}
+}
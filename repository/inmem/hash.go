@@ -4,37 +4,75 @@ import (
 	"sync"
 
 	"github.com/dolefir/refresh-hash/models"
+	"github.com/dolefir/refresh-hash/repository"
 )
 
 // Repository holds methods for works with hash data inmem.
 type Repository struct {
-	hash models.Hash
+	namespaces map[string]*namespace
+	retention  repository.Retention
 	*sync.RWMutex
 }
 
+// namespace holds the current hash and history of a single namespace.
+type namespace struct {
+	hash    models.Hash
+	history []models.Hash
+}
+
 // NewRepository returns new hash Repository.
-func NewRepository() *Repository {
+func NewRepository(retention repository.Retention) *Repository {
 	return &Repository{
-		RWMutex: new(sync.RWMutex),
+		namespaces: make(map[string]*namespace),
+		retention:  retention,
+		RWMutex:    new(sync.RWMutex),
 	}
 }
 
 // Set the information record.
 func (r *Repository) Set(h *models.Hash) error {
 	r.RWMutex.Lock()
-	r.hash.ID = h.ID
-	r.hash.Datatime = h.Datatime
+	ns, ok := r.namespaces[h.Namespace]
+	if !ok {
+		ns = &namespace{}
+		r.namespaces[h.Namespace] = ns
+	}
+	ns.hash.ID = h.ID
+	ns.hash.Datatime = h.Datatime
+	ns.hash.Generator = h.Generator
+	ns.hash.Namespace = h.Namespace
+	ns.history = r.retention.Apply(append([]models.Hash{ns.hash}, ns.history...), ns.hash.Datatime)
 	r.RWMutex.Unlock()
 
 	return nil
 }
 
-// Get the read information.
-// It is possible not to use Repository as
-// a pointer it is only for the read.
-func (r *Repository) Get() (*models.Hash, error) {
+// Get the read information, it returns a copy
+// not changed by a concurrent Set.
+func (r *Repository) Get(namespace string) (*models.Hash, error) {
 	r.RWMutex.RLock()
 	defer r.RWMutex.RUnlock()
 
-	return &r.hash, nil
+	hash := models.Hash{Namespace: namespace}
+	if ns, ok := r.namespaces[namespace]; ok {
+		hash = ns.hash
+	}
+
+	return &hash, nil
+}
+
+// List returns retained hashes of the namespace, newest first.
+func (r *Repository) List(namespace string) ([]models.Hash, error) {
+	r.RWMutex.RLock()
+	defer r.RWMutex.RUnlock()
+
+	ns, ok := r.namespaces[namespace]
+	if !ok {
+		return []models.Hash{}, nil
+	}
+
+	history := make([]models.Hash, len(ns.history))
+	copy(history, ns.history)
+
+	return history, nil
 }
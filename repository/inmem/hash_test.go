@@ -0,0 +1,140 @@
+package inmem
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dolefir/refresh-hash/models"
+	"github.com/dolefir/refresh-hash/repository"
+)
+
+func TestRepository_Namespaces(t *testing.T) {
+	r := NewRepository(repository.Retention{})
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	for _, h := range []models.Hash{
+		{ID: "default-1", Datatime: now, Namespace: models.DefaultNamespace},
+		{ID: "csrf-1", Datatime: now, Namespace: "csrf"},
+	} {
+		h := h
+		if err := r.Set(&h); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		namespace string
+		want      *models.Hash
+	}{
+		{
+			name:      "should returns hash of the default namespace",
+			namespace: models.DefaultNamespace,
+			want:      &models.Hash{ID: "default-1", Datatime: now, Namespace: models.DefaultNamespace},
+		},
+		{
+			name:      "should returns hash of a named namespace",
+			namespace: "csrf",
+			want:      &models.Hash{ID: "csrf-1", Datatime: now, Namespace: "csrf"},
+		},
+		{
+			name:      "should returns empty hash of an unknown namespace",
+			namespace: "session",
+			want:      &models.Hash{Namespace: "session"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.Get(tt.namespace)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Repository.Get() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRepository_History(t *testing.T) {
+	start := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name      string
+		retention repository.Retention
+		want      []string
+	}{
+		{
+			name: "should keep all hashes newest first",
+			want: []string{"hash-4", "hash-3", "hash-2", "hash-1"},
+		},
+		{
+			name:      "should keep max count",
+			retention: repository.Retention{MaxCount: 2},
+			want:      []string{"hash-4", "hash-3"},
+		},
+		{
+			name:      "should drop hashes replaced before max age",
+			retention: repository.Retention{MaxAge: 90 * time.Minute},
+			want:      []string{"hash-4", "hash-3", "hash-2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRepository(tt.retention)
+			for i := 1; i <= 4; i++ {
+				h := &models.Hash{
+					ID:        "hash-" + string(rune('0'+i)),
+					Datatime:  start.Add(time.Duration(i) * time.Hour),
+					Namespace: "csrf",
+				}
+				if err := r.Set(h); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			history, err := r.List("csrf")
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, h := range history {
+				got = append(got, h.ID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Repository.List() = %v, want %v", got, tt.want)
+			}
+
+			// The returned history is a copy.
+			history[0].ID = "changed"
+			if again, _ := r.List("csrf"); again[0].ID != tt.want[0] {
+				t.Error("Repository.List() returns the repository state")
+			}
+		})
+	}
+}
+
+func TestRepository_GetCopy(t *testing.T) {
+	r := NewRepository(repository.Retention{})
+	if err := r.Set(&models.Hash{ID: "hash-1", Namespace: "csrf"}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := r.Get("csrf")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Set while the returned hash is read, run with -race.
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_ = r.Set(&models.Hash{ID: "hash-2", Namespace: "csrf"})
+	}()
+	id := got.ID
+	wg.Wait()
+
+	if id != "hash-1" || got.ID != "hash-1" {
+		t.Errorf("Repository.Get() = %v, changed by Set", got)
+	}
+}
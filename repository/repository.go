@@ -1,9 +1,53 @@
 package repository
 
-import "github.com/dolefir/refresh-hash/models"
+import (
+	"time"
+
+	"github.com/dolefir/refresh-hash/models"
+)
 
 // Inmem is the interface that wraps works in-memory with hash.
+// Hashes are stored per namespace, Set uses the hash Namespace.
 type Inmem interface {
 	Set(h *models.Hash) error
-	Get() (*models.Hash, error)
+	Get(namespace string) (*models.Hash, error)
+	// List returns retained hashes of the namespace, newest first.
+	List(namespace string) ([]models.Hash, error)
+}
+
+// Pinger is implemented by repositories whose storage can become
+// unavailable, Ping returns an error while it is.
+type Pinger interface {
+	Ping() error
+}
+
+// Retention bounds the hash history kept by a repository.
+// A zero value field means no limit by that field.
+type Retention struct {
+	// MaxCount is the maximum number of retained hashes,
+	// including the current one.
+	MaxCount int
+	// MaxAge drops hashes replaced earlier than MaxAge ago.
+	MaxAge time.Duration
+}
+
+// Apply trims the newest-first history according to the retention.
+// The current hash is always kept, now is usually its Datatime.
+func (r Retention) Apply(history []models.Hash, now time.Time) []models.Hash {
+	if r.MaxCount > 0 && len(history) > r.MaxCount {
+		history = history[:r.MaxCount]
+	}
+
+	if r.MaxAge > 0 {
+		deadline := now.Add(-r.MaxAge)
+		for i := 1; i < len(history); i++ {
+			// history[i] stopped being active when history[i-1] was set.
+			if history[i-1].Datatime.Before(deadline) {
+				history = history[:i]
+				break
+			}
+		}
+	}
+
+	return history
 }
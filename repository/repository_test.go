@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dolefir/refresh-hash/models"
+)
+
+func TestRetention_Apply(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 0, 0, 0, time.UTC)
+	history := []models.Hash{
+		{ID: "4", Datatime: now},
+		{ID: "3", Datatime: now.Add(-5 * time.Minute)},
+		{ID: "2", Datatime: now.Add(-10 * time.Minute)},
+		{ID: "1", Datatime: now.Add(-15 * time.Minute)},
+	}
+
+	tests := []struct {
+		name      string
+		retention Retention
+		want      []string
+	}{
+		{
+			name: "should keep everything without limits",
+			want: []string{"4", "3", "2", "1"},
+		},
+		{
+			name:      "should keep last N hashes",
+			retention: Retention{MaxCount: 2},
+			want:      []string{"4", "3"},
+		},
+		{
+			name:      "should keep hashes active within max age",
+			retention: Retention{MaxAge: 7 * time.Minute},
+			want:      []string{"4", "3", "2"},
+		},
+		{
+			name:      "should always keep the current hash",
+			retention: Retention{MaxCount: 1, MaxAge: time.Nanosecond},
+			want:      []string{"4"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.retention.Apply(history, now)
+			ids := make([]string, 0, len(got))
+			for _, h := range got {
+				ids = append(ids, h.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.want) {
+				t.Errorf("Retention.Apply() = %v, want %v", ids, tt.want)
+			}
+		})
+	}
+}
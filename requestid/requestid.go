@@ -0,0 +1,61 @@
+package requestid
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// Header is the HTTP header carrying the request ID.
+	Header = "X-Request-ID"
+	// MetadataKey is the gRPC metadata key carrying the request ID.
+	MetadataKey = "x-request-id"
+	// LogField is the log field of the request ID.
+	LogField = "request_id"
+)
+
+// maxLength limits an incoming ID written to logs and headers.
+const maxLength = 128
+
+type contextKey struct{}
+
+// New returns a new random request ID.
+func New() string {
+	return uuid.NewString()
+}
+
+// Valid reports whether an incoming ID may be used as is,
+// it must be short printable ASCII without spaces.
+func Valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Ensure returns the incoming ID if it is valid, a new one otherwise.
+func Ensure(id string) string {
+	if Valid(id) {
+		return id
+	}
+
+	return New()
+}
+
+// NewContext returns a copy of ctx carrying the request ID.
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the request ID of ctx, false if there is none.
+func FromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(contextKey{}).(string)
+	return id, ok && id != ""
+}
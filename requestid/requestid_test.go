@@ -0,0 +1,43 @@
+package requestid
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestEnsure(t *testing.T) {
+	tests := []struct {
+		name   string
+		id     string
+		wantAs bool
+	}{
+		{name: "should keep valid id", id: "2f1c3a-req.42", wantAs: true},
+		{name: "should replace empty id", id: ""},
+		{name: "should replace id with spaces", id: "a b"},
+		{name: "should replace id with new line", id: "a\nb"},
+		{name: "should replace long id", id: strings.Repeat("a", maxLength+1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Ensure(tt.id)
+			if (got == tt.id) != tt.wantAs {
+				t.Errorf("Ensure(%q) = %q", tt.id, got)
+			}
+			if !Valid(got) {
+				t.Errorf("Ensure(%q) = %q is not valid", tt.id, got)
+			}
+		})
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	if _, ok := FromContext(context.Background()); ok {
+		t.Error("unexpected request ID in empty context")
+	}
+
+	id, ok := FromContext(NewContext(context.Background(), "req-1"))
+	if !ok || id != "req-1" {
+		t.Errorf("FromContext() = %q, %v", id, ok)
+	}
+}
@@ -0,0 +1,45 @@
+package accesslog
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/dolefir/refresh-hash/config"
+)
+
+// Policy decides which requests are written to the access log.
+type Policy struct {
+	skip   map[string]bool
+	sample map[string]float64
+	random func() float64
+}
+
+// NewPolicy returns the policy of the config, routes are REST
+// route templates like /api/hash/:name or full gRPC methods.
+func NewPolicy(cfg config.AccessLog) *Policy {
+	p := &Policy{
+		skip:   make(map[string]bool, len(cfg.Skip)),
+		sample: cfg.Sample,
+		random: rand.New(rand.NewSource(time.Now().UnixNano())).Float64,
+	}
+	for _, route := range cfg.Skip {
+		p.skip[route] = true
+	}
+
+	return p
+}
+
+// Allow reports whether a request of the route is logged. A route in
+// the skip list is never logged, a sampled route is logged with its
+// rate unless the request failed.
+func (p *Policy) Allow(route string, failed bool) bool {
+	if p.skip[route] {
+		return false
+	}
+	rate, ok := p.sample[route]
+	if !ok || failed {
+		return true
+	}
+
+	return p.random() < rate
+}
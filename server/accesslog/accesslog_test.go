@@ -0,0 +1,35 @@
+package accesslog
+
+import (
+	"testing"
+
+	"github.com/dolefir/refresh-hash/config"
+)
+
+func TestPolicy_Allow(t *testing.T) {
+	p := NewPolicy(config.AccessLog{
+		Skip:   []string{"/healthz"},
+		Sample: map[string]float64{"/api/hash": 0.1},
+	})
+	p.random = func() float64 { return 0.5 }
+
+	tests := []struct {
+		name   string
+		route  string
+		failed bool
+		want   bool
+	}{
+		{name: "should log route without sampling", route: "/api/hash/refresh", want: true},
+		{name: "should skip route", route: "/healthz"},
+		{name: "should skip failed request of skipped route", route: "/healthz", failed: true},
+		{name: "should drop sampled out request", route: "/api/hash"},
+		{name: "should log failed request of sampled route", route: "/api/hash", failed: true, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := p.Allow(tt.route, tt.failed); got != tt.want {
+				t.Errorf("Policy.Allow() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	gen "github.com/dolefir/refresh-hash/gen/proto"
+	"github.com/dolefir/refresh-hash/logger"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// LevelControl reads and changes the log level at runtime.
+type LevelControl interface {
+	Get() logger.LevelState
+	Set(level string, revertAfter time.Duration) (logger.LevelState, error)
+}
+
+type AdminService struct {
+	gen.UnimplementedAdminServiceServer
+	levels LevelControl
+}
+
+func NewAdminService(levels LevelControl) *AdminService {
+	return &AdminService{
+		levels: levels,
+	}
+}
+
+func (as AdminService) GetLogLevel(ctx context.Context, in *gen.GetLogLevelRequest) (*gen.LogLevelResponse, error) {
+	return logLevelResponse(as.levels.Get()), nil
+}
+
+func (as AdminService) SetLogLevel(ctx context.Context, in *gen.SetLogLevelRequest) (*gen.LogLevelResponse, error) {
+	var revertAfter time.Duration
+	if in.GetRevertAfter() != nil {
+		if err := in.GetRevertAfter().CheckValid(); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		revertAfter = in.GetRevertAfter().AsDuration()
+	}
+
+	state, err := as.levels.Set(in.GetLevel(), revertAfter)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return logLevelResponse(state), nil
+}
+
+func logLevelResponse(state logger.LevelState) *gen.LogLevelResponse {
+	out := &gen.LogLevelResponse{Level: state.Level}
+	if state.RevertAt != nil {
+		out.RevertAt = timestamppb.New(*state.RevertAt)
+	}
+
+	return out
+}
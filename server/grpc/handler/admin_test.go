@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dolefir/refresh-hash/config"
+	gen "github.com/dolefir/refresh-hash/gen/proto"
+	"github.com/dolefir/refresh-hash/logger"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestAdminService_SetLogLevel(t *testing.T) {
+	cfg := config.NewConfig("")
+	log := logger.NewLogger((*logger.CFGLogger)(&cfg.Logger), nil)
+	as := NewAdminService(logger.NewLevelControl(log))
+
+	tests := []struct {
+		name     string
+		in       *gen.SetLogLevelRequest
+		wantCode codes.Code
+		revert   bool
+	}{
+		{
+			name: "should set level",
+			in:   &gen.SetLogLevelRequest{Level: "error"},
+		},
+		{
+			name:   "should set temporary level",
+			in:     &gen.SetLogLevelRequest{Level: "debug", RevertAfter: durationpb.New(time.Hour)},
+			revert: true,
+		},
+		{
+			name:     "should reject unknown level",
+			in:       &gen.SetLogLevelRequest{Level: "verbose"},
+			wantCode: codes.InvalidArgument,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := as.SetLogLevel(context.Background(), tt.in)
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("AdminService.SetLogLevel() error = %v, wantCode %v", err, tt.wantCode)
+			}
+			if err != nil {
+				return
+			}
+			if got.GetLevel() != tt.in.GetLevel() || (got.GetRevertAt() != nil) != tt.revert {
+				t.Errorf("AdminService.SetLogLevel() = %v", got)
+			}
+			if current, _ := as.GetLogLevel(context.Background(), &gen.GetLogLevelRequest{}); current.GetLevel() != tt.in.GetLevel() {
+				t.Errorf("AdminService.GetLogLevel() = %v, want %v", current.GetLevel(), tt.in.GetLevel())
+			}
+		})
+	}
+}
@@ -2,25 +2,170 @@ package handler
 
 import (
 	"context"
+	"errors"
+	"sync"
+	"time"
 
 	gen "github.com/dolefir/refresh-hash/gen/proto"
+	"github.com/dolefir/refresh-hash/models"
 	"github.com/dolefir/refresh-hash/services"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+var hashStates = map[string]gen.HashState{
+	models.HashStateCurrent: gen.HashState_HASH_STATE_CURRENT,
+	models.HashStateGrace:   gen.HashState_HASH_STATE_GRACE,
+	models.HashStateInvalid: gen.HashState_HASH_STATE_INVALID,
+}
+
 type HashService struct {
 	gen.UnimplementedHashServiceServer
-	hashSrv services.Hash
+	hashSrv   services.Hash
+	done      chan struct{}
+	closeOnce *sync.Once
+	// readOnly rejects RefreshHash, it is served by the admin server.
+	readOnly bool
 }
 
-func NewHashService(hashSrv services.Hash) *HashService {
-	return &HashService{hashSrv: hashSrv}
+// Option options for HashService setup.
+type Option func(hs *HashService)
+
+// ReadOnly rejects RefreshHash with PermissionDenied.
+func ReadOnly() Option {
+	return func(hs *HashService) {
+		hs.readOnly = true
+	}
+}
+
+func NewHashService(hashSrv services.Hash, options ...Option) *HashService {
+	hs := &HashService{
+		hashSrv:   hashSrv,
+		done:      make(chan struct{}),
+		closeOnce: new(sync.Once),
+	}
+	for _, option := range options {
+		option(hs)
+	}
+
+	return hs
+}
+
+// Close ends all open WatchHash streams, it must be called
+// before GracefulStop, which waits for the streams to finish.
+func (hs HashService) Close() {
+	hs.closeOnce.Do(func() {
+		close(hs.done)
+	})
 }
 
 func (hs HashService) GetHash(ctx context.Context, in *gen.GetHashRequest) (*gen.GetHashResponse, error) {
-	resp, err := hs.hashSrv.Get(ctx)
+	resp, err := hs.hashSrv.Get(ctx, namespace(in.GetName()))
 	if err != nil {
-		return nil, err
+		return nil, statusError(err)
+	}
+
+	out := &gen.GetHashResponse{
+		Uid:       resp.ID,
+		CreatedAt: timestamppb.New(resp.Datatime),
+		Generator: resp.Generator,
+		Namespace: resp.Namespace,
+	}
+	if resp.ExpiresAt != nil {
+		out.ExpiresAt = timestamppb.New(*resp.ExpiresAt)
+	}
+	if resp.TTL != nil {
+		out.Ttl = durationpb.New(time.Duration(*resp.TTL) * time.Second)
+	}
+
+	return out, nil
+}
+
+func (hs HashService) ValidateHash(ctx context.Context, in *gen.ValidateHashRequest) (*gen.ValidateHashResponse, error) {
+	resp, err := hs.hashSrv.Validate(ctx, namespace(in.GetName()), in.GetUid())
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	out := &gen.ValidateHashResponse{Valid: resp.Valid, State: hashStates[resp.State]}
+	if resp.ValidUntil != nil {
+		out.ValidUntil = timestamppb.New(*resp.ValidUntil)
+	}
+
+	return out, nil
+}
+
+func (hs HashService) RefreshHash(ctx context.Context, in *gen.RefreshHashRequest) (*gen.RefreshHashResponse, error) {
+	if hs.readOnly {
+		return nil, status.Error(codes.PermissionDenied, "refresh is served by the admin server")
+	}
+
+	name := namespace(in.GetName())
+	resp, err := hs.hashSrv.Refresh(ctx, name)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return &gen.RefreshHashResponse{Uid: resp.ID}, nil
+}
+
+// WatchHash sends the current hash and then every rotation
+// until the client goes away or the server is shutting down.
+func (hs HashService) WatchHash(in *gen.WatchHashRequest, stream gen.HashService_WatchHashServer) error {
+	ctx := stream.Context()
+	name := namespace(in.GetName())
+
+	// Subscribe before reading the current hash to not miss a rotation.
+	hashes, err := hs.hashSrv.Watch(ctx, name)
+	if err != nil {
+		return statusError(err)
+	}
+
+	current, err := hs.hashSrv.Get(ctx, name)
+	if err != nil {
+		return statusError(err)
+	}
+	if err := stream.Send(&gen.WatchHashResponse{Uid: current.ID}); err != nil {
+		return err
+	}
+
+	last := current.ID
+	for {
+		select {
+		case hash, ok := <-hashes:
+			if !ok {
+				// Canceled or DeadlineExceeded, not Unknown.
+				return status.FromContextError(ctx.Err()).Err()
+			}
+			if hash.ID == last {
+				continue
+			}
+			if err := stream.Send(&gen.WatchHashResponse{Uid: hash.ID}); err != nil {
+				return err
+			}
+			last = hash.ID
+
+		case <-hs.done:
+			return status.Error(codes.Unavailable, "server is shutting down")
+		}
+	}
+}
+
+// namespace returns the requested namespace, empty is the default one.
+func namespace(name string) string {
+	if name == "" {
+		return models.DefaultNamespace
+	}
+
+	return name
+}
+
+func statusError(err error) error {
+	if errors.Is(err, services.ErrNamespaceNotFound) {
+		return status.Error(codes.NotFound, err.Error())
 	}
 
-	return &gen.GetHashResponse{Uid: resp.ID}, nil
+	return err
 }
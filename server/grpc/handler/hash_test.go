@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	gen "github.com/dolefir/refresh-hash/gen/proto"
+	"github.com/dolefir/refresh-hash/models"
+	"github.com/dolefir/refresh-hash/services"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+type hashServiceMock struct {
+	services.Hash
+	hash *models.Hash
+}
+
+func (m hashServiceMock) Get(ctx context.Context, namespace string) (*models.Hash, error) {
+	return m.hash, nil
+}
+
+// Validate reports the hash in the grace state until an hour after its creation.
+func (m hashServiceMock) Validate(ctx context.Context, namespace, id string) (*models.Validation, error) {
+	validUntil := m.hash.Datatime.Add(time.Hour)
+	return &models.Validation{Valid: true, State: models.HashStateGrace, ValidUntil: &validUntil}, nil
+}
+
+func TestHashService_ValidateHash(t *testing.T) {
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	hs := NewHashService(hashServiceMock{hash: &models.Hash{Datatime: createdAt}})
+
+	got, err := hs.ValidateHash(context.Background(), &gen.ValidateHashRequest{Uid: "previous"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.GetValid() ||
+		got.GetState() != gen.HashState_HASH_STATE_GRACE ||
+		!got.GetValidUntil().AsTime().Equal(createdAt.Add(time.Hour)) {
+		t.Errorf("HashService.ValidateHash() = %v", got)
+	}
+}
+
+// Watch returns a channel closed once ctx is done.
+func (m hashServiceMock) Watch(ctx context.Context, namespace string) (<-chan models.Hash, error) {
+	ch := make(chan models.Hash)
+	go func() {
+		<-ctx.Done()
+		close(ch)
+	}()
+
+	return ch, nil
+}
+
+type watchStreamMock struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent chan *gen.WatchHashResponse
+}
+
+func (s *watchStreamMock) Context() context.Context {
+	return s.ctx
+}
+
+func (s *watchStreamMock) Send(resp *gen.WatchHashResponse) error {
+	s.sent <- resp
+	return nil
+}
+
+func TestHashService_WatchHashCanceled(t *testing.T) {
+	hs := NewHashService(hashServiceMock{hash: &models.Hash{ID: "996f2357-31af-4b1a-9889-a075be3de0a9"}})
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := &watchStreamMock{ctx: ctx, sent: make(chan *gen.WatchHashResponse, 1)}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- hs.WatchHash(&gen.WatchHashRequest{}, stream)
+	}()
+	<-stream.sent
+	cancel()
+
+	if code := status.Code(<-done); code != codes.Canceled {
+		t.Errorf("HashService.WatchHash() code = %v, want %v", code, codes.Canceled)
+	}
+}
+
+func (m hashServiceMock) Refresh(ctx context.Context, namespace string) (*models.Hash, error) {
+	return m.hash, nil
+}
+
+func TestHashService_RefreshHash(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  []Option
+		wantCode codes.Code
+	}{
+		{
+			name:     "should refresh the hash",
+			wantCode: codes.OK,
+		},
+		{
+			name:     "should reject refresh when read only",
+			options:  []Option{ReadOnly()},
+			wantCode: codes.PermissionDenied,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hs := NewHashService(hashServiceMock{hash: &models.Hash{ID: "hash-1"}}, tt.options...)
+
+			got, err := hs.RefreshHash(context.Background(), &gen.RefreshHashRequest{})
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("HashService.RefreshHash() code = %v, want %v", code, tt.wantCode)
+			}
+			if err == nil && got.GetUid() != "hash-1" {
+				t.Errorf("HashService.RefreshHash() = %v", got)
+			}
+		})
+	}
+}
+
+func TestHashService_GetHash(t *testing.T) {
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	expiresAt := createdAt.Add(5 * time.Minute)
+	ttl := int64(60)
+	hs := NewHashService(hashServiceMock{hash: &models.Hash{
+		ID:        "996f2357-31af-4b1a-9889-a075be3de0a9",
+		Datatime:  createdAt,
+		Generator: "uuid4",
+		Namespace: models.DefaultNamespace,
+		ExpiresAt: &expiresAt,
+		TTL:       &ttl,
+	}})
+
+	got, err := hs.GetHash(context.Background(), &gen.GetHashRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.GetUid() != "996f2357-31af-4b1a-9889-a075be3de0a9" ||
+		!got.GetCreatedAt().AsTime().Equal(createdAt) ||
+		!got.GetExpiresAt().AsTime().Equal(expiresAt) ||
+		got.GetTtl().AsDuration() != time.Minute ||
+		got.GetGenerator() != "uuid4" ||
+		got.GetNamespace() != models.DefaultNamespace {
+		t.Errorf("HashService.GetHash() = %v", got)
+	}
+
+	// A client built before the new fields only knows uid = 1,
+	// RefreshHashResponse has exactly that shape.
+	data, err := proto.Marshal(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var old gen.RefreshHashResponse
+	if err := proto.Unmarshal(data, &old); err != nil {
+		t.Fatal(err)
+	}
+	if old.GetUid() != got.GetUid() {
+		t.Errorf("old client uid = %v, want %v", old.GetUid(), got.GetUid())
+	}
+}
@@ -0,0 +1,95 @@
+package interceptors
+
+import (
+	"context"
+	"time"
+
+	"github.com/dolefir/refresh-hash/logger"
+	"github.com/dolefir/refresh-hash/requestid"
+	"github.com/dolefir/refresh-hash/server/accesslog"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// UnaryAccessLog writes a log line for every unary call allowed by the policy.
+func UnaryAccessLog(log logger.Logger, policy *accesslog.Policy) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+
+		var size int
+		if msg, ok := resp.(proto.Message); ok && err == nil {
+			size = proto.Size(msg)
+		}
+		logCall(ctx, log, policy, info.FullMethod, status.Code(err), time.Since(start), size)
+
+		return resp, err
+	}
+}
+
+// StreamAccessLog writes a log line for every streaming call allowed by the policy.
+func StreamAccessLog(log logger.Logger, policy *accesslog.Policy) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		logCall(ss.Context(), log, policy, info.FullMethod, status.Code(err), time.Since(start), 0)
+
+		return err
+	}
+}
+
+func logCall(
+	ctx context.Context,
+	log logger.Logger,
+	policy *accesslog.Policy,
+	method string,
+	code codes.Code,
+	latency time.Duration,
+	size int,
+) {
+	failed := serverError(code)
+	if !policy.Allow(method, failed) {
+		return
+	}
+
+	var clientIP string
+	if p, ok := peer.FromContext(ctx); ok {
+		clientIP = p.Addr.String()
+	}
+	id, _ := requestid.FromContext(ctx)
+	fields := []interface{}{
+		"method", method,
+		"code", code.String(),
+		"latency", latency,
+		"bytes", size,
+		"client_ip", clientIP,
+		requestid.LogField, id,
+	}
+	if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+		fields = append(fields, "trace_id", span.TraceID().String())
+	}
+	switch {
+	case failed:
+		log.Errorw("grpc request", fields...)
+	case code != codes.OK:
+		log.Warnw("grpc request", fields...)
+	default:
+		log.Infow("grpc request", fields...)
+	}
+}
+
+// serverError reports whether the code is a failure of the server,
+// other codes than OK are caused by the client.
+func serverError(code codes.Code) bool {
+	switch code {
+	case codes.Unknown, codes.DeadlineExceeded, codes.Unimplemented,
+		codes.Internal, codes.Unavailable, codes.DataLoss:
+		return true
+	default:
+		return false
+	}
+}
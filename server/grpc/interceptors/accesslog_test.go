@@ -0,0 +1,196 @@
+package interceptors
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dolefir/refresh-hash/config"
+	"github.com/dolefir/refresh-hash/logger"
+	"github.com/dolefir/refresh-hash/requestid"
+	"github.com/dolefir/refresh-hash/server/accesslog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// logEntry is a log line written with key-value pairs.
+type logEntry struct {
+	level  string
+	msg    string
+	fields map[string]interface{}
+}
+
+// logMock records log lines written with key-value pairs.
+type logMock struct {
+	logger.Logger
+	entries *[]logEntry
+}
+
+func newLogMock() logMock {
+	return logMock{entries: new([]logEntry)}
+}
+
+func (l logMock) record(level, msg string, args []interface{}) {
+	fields := make(map[string]interface{}, len(args)/2)
+	for i := 0; i+1 < len(args); i += 2 {
+		fields[args[i].(string)] = args[i+1]
+	}
+	*l.entries = append(*l.entries, logEntry{level: level, msg: msg, fields: fields})
+}
+
+func (l logMock) Infow(msg string, args ...interface{})  { l.record("info", msg, args) }
+func (l logMock) Warnw(msg string, args ...interface{})  { l.record("warn", msg, args) }
+func (l logMock) Errorw(msg string, args ...interface{}) { l.record("error", msg, args) }
+
+const watchMethod = "/hash.HashService/WatchHash"
+
+func TestUnaryAccessLog(t *testing.T) {
+	tests := []struct {
+		name      string
+		method    string
+		err       error
+		wantLevel string
+		wantCode  string
+	}{
+		{
+			name:      "should log a call",
+			method:    "/hash.HashService/GetHash",
+			wantLevel: "info",
+			wantCode:  "OK",
+		},
+		{
+			name:      "should log a client error as warning",
+			method:    "/hash.HashService/GetHash",
+			err:       status.Error(codes.NotFound, "not found"),
+			wantLevel: "warn",
+			wantCode:  "NotFound",
+		},
+		{
+			name:      "should log a server error as error",
+			method:    "/hash.HashService/GetHash",
+			err:       errors.New("boom"),
+			wantLevel: "error",
+			wantCode:  "Unknown",
+		},
+		{
+			name:   "should skip a method",
+			method: "/grpc.health.v1.Health/Check",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := newLogMock()
+			policy := accesslog.NewPolicy(config.AccessLog{Skip: []string{"/grpc.health.v1.Health/Check"}})
+			ctx := requestid.NewContext(context.Background(), "req-1")
+
+			_, _ = UnaryAccessLog(log, policy)(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method},
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					if tt.err != nil {
+						return nil, tt.err
+					}
+					return &emptypb.Empty{}, nil
+				})
+
+			if tt.wantLevel == "" {
+				if len(*log.entries) != 0 {
+					t.Fatalf("skipped call logged: %v", *log.entries)
+				}
+				return
+			}
+			if len(*log.entries) != 1 {
+				t.Fatalf("wrong number of log lines (%d), expected - 1", len(*log.entries))
+			}
+			entry := (*log.entries)[0]
+			if entry.level != tt.wantLevel {
+				t.Errorf("wrong level %s, expected - %s", entry.level, tt.wantLevel)
+			}
+			for _, key := range []string{"method", "code", "latency", "bytes", "client_ip", requestid.LogField} {
+				if _, ok := entry.fields[key]; !ok {
+					t.Errorf("no %s field in %v", key, entry.fields)
+				}
+			}
+			if entry.fields["method"] != tt.method || entry.fields["code"] != tt.wantCode || entry.fields[requestid.LogField] != "req-1" {
+				t.Errorf("wrong fields %v", entry.fields)
+			}
+		})
+	}
+}
+
+func TestStreamAccessLog(t *testing.T) {
+	tests := []struct {
+		name     string
+		skip     []string
+		err      error
+		wantLogs int
+		wantCode string
+	}{
+		{
+			name:     "should log a canceled stream",
+			err:      status.Error(codes.Canceled, "canceled"),
+			wantLogs: 1,
+			wantCode: "Canceled",
+		},
+		{
+			name: "should skip a method",
+			skip: []string{watchMethod},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := newLogMock()
+			policy := accesslog.NewPolicy(config.AccessLog{Skip: tt.skip})
+			ss := &serverStreamMock{ctx: requestid.NewContext(context.Background(), "req-1")}
+
+			err := StreamAccessLog(log, policy)(nil, ss, &grpc.StreamServerInfo{FullMethod: watchMethod},
+				func(srv interface{}, stream grpc.ServerStream) error {
+					return tt.err
+				})
+			if !errors.Is(err, tt.err) {
+				t.Errorf("wrong error %v, expected - %v", err, tt.err)
+			}
+
+			if len(*log.entries) != tt.wantLogs {
+				t.Fatalf("wrong number of log lines (%d), expected - %d", len(*log.entries), tt.wantLogs)
+			}
+			if tt.wantLogs > 0 {
+				entry := (*log.entries)[0]
+				if entry.fields["method"] != watchMethod || entry.fields["code"] != tt.wantCode || entry.fields[requestid.LogField] != "req-1" {
+					t.Errorf("wrong fields %v", entry.fields)
+				}
+			}
+		})
+	}
+}
+
+func TestRecovery(t *testing.T) {
+	log := newLogMock()
+	ctx := requestid.NewContext(context.Background(), "req-1")
+
+	_, err := UnaryRecovery(log)(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/hash.HashService/GetHash"},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			panic("boom")
+		})
+	if status.Code(err) != codes.Internal {
+		t.Errorf("unary: wrong code %v, expected - %v", status.Code(err), codes.Internal)
+	}
+
+	ss := &serverStreamMock{ctx: ctx}
+	err = StreamRecovery(log)(nil, ss, &grpc.StreamServerInfo{FullMethod: watchMethod},
+		func(srv interface{}, stream grpc.ServerStream) error {
+			panic("boom")
+		})
+	if status.Code(err) != codes.Internal {
+		t.Errorf("stream: wrong code %v, expected - %v", status.Code(err), codes.Internal)
+	}
+
+	if len(*log.entries) != 2 {
+		t.Fatalf("wrong number of log lines (%d), expected - 2", len(*log.entries))
+	}
+	for _, entry := range *log.entries {
+		if entry.level != "error" || entry.fields["error"] != "boom" || entry.fields[requestid.LogField] != "req-1" || entry.fields["stack"] == "" {
+			t.Errorf("wrong panic log %v", entry)
+		}
+	}
+}
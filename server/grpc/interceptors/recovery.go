@@ -0,0 +1,53 @@
+package interceptors
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+
+	"github.com/dolefir/refresh-hash/logger"
+	"github.com/dolefir/refresh-hash/requestid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryRecovery logs a panic of a unary handler with its stack
+// and returns codes.Internal instead of crashing the server.
+func UnaryRecovery(log logger.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recovered(ctx, log, info.FullMethod, r)
+			}
+		}()
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamRecovery logs a panic of a streaming handler with its stack
+// and returns codes.Internal instead of crashing the server.
+func StreamRecovery(log logger.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recovered(ss.Context(), log, info.FullMethod, r)
+			}
+		}()
+
+		return handler(srv, ss)
+	}
+}
+
+func recovered(ctx context.Context, log logger.Logger, method string, r interface{}) error {
+	id, _ := requestid.FromContext(ctx)
+	log.Errorw("panic recovered",
+		"error", fmt.Sprint(r),
+		"method", method,
+		requestid.LogField, id,
+		"stack", string(debug.Stack()),
+	)
+
+	return status.Error(codes.Internal, "internal error")
+}
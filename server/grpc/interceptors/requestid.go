@@ -0,0 +1,63 @@
+package interceptors
+
+import (
+	"context"
+
+	"github.com/dolefir/refresh-hash/logger"
+	"github.com/dolefir/refresh-hash/requestid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// UnaryRequestID takes the request ID from the x-request-id metadata
+// or generates one, puts it into the context together with a logger
+// of the call and returns it in the header.
+func UnaryRequestID(log logger.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		id := incomingRequestID(ctx)
+		_ = grpc.SetHeader(ctx, metadata.Pairs(requestid.MetadataKey, id))
+
+		return handler(requestContext(ctx, log, id, info.FullMethod), req)
+	}
+}
+
+// StreamRequestID is UnaryRequestID of streaming calls.
+func StreamRequestID(log logger.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		id := incomingRequestID(ss.Context())
+		_ = ss.SetHeader(metadata.Pairs(requestid.MetadataKey, id))
+
+		return handler(srv, &contextStream{
+			ServerStream: ss,
+			ctx:          requestContext(ss.Context(), log, id, info.FullMethod),
+		})
+	}
+}
+
+// requestContext returns ctx carrying the request ID and a logger of the method.
+func requestContext(ctx context.Context, log logger.Logger, id, method string) context.Context {
+	ctx = requestid.NewContext(ctx, id)
+	return logger.WithContext(ctx, log.With("grpc_method", method))
+}
+
+func incomingRequestID(ctx context.Context) string {
+	var id string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(requestid.MetadataKey); len(values) > 0 {
+			id = values[0]
+		}
+	}
+
+	return requestid.Ensure(id)
+}
+
+// contextStream replaces the context of a server stream.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the replaced context.
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
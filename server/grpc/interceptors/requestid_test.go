@@ -0,0 +1,76 @@
+package interceptors
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dolefir/refresh-hash/config"
+	"github.com/dolefir/refresh-hash/logger"
+	"github.com/dolefir/refresh-hash/requestid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+type serverStreamMock struct {
+	grpc.ServerStream
+	ctx    context.Context
+	header metadata.MD
+}
+
+func (s *serverStreamMock) Context() context.Context {
+	return s.ctx
+}
+
+func (s *serverStreamMock) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func TestStreamRequestID(t *testing.T) {
+	tests := []struct {
+		name     string
+		incoming metadata.MD
+		want     string
+	}{
+		{
+			name:     "should keep incoming id",
+			incoming: metadata.Pairs(requestid.MetadataKey, "req-1"),
+			want:     "req-1",
+		},
+		{
+			name: "should generate id",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.NewConfig("")
+			log := logger.NewLogger((*logger.CFGLogger)(&cfg.Logger), nil)
+			ctx := metadata.NewIncomingContext(context.Background(), tt.incoming)
+			ss := &serverStreamMock{ctx: ctx}
+
+			var got string
+			var reqLog logger.Logger
+			err := StreamRequestID(log)(nil, ss, &grpc.StreamServerInfo{}, func(srv interface{}, stream grpc.ServerStream) error {
+				got, _ = requestid.FromContext(stream.Context())
+				reqLog = logger.FromContext(stream.Context(), nil)
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if reqLog == nil {
+				t.Error("no request logger in the context")
+			}
+			if tt.want != "" && got != tt.want {
+				t.Errorf("wrong request ID (%s), expected - %s", got, tt.want)
+			}
+			if !requestid.Valid(got) {
+				t.Errorf("invalid request ID %q", got)
+			}
+			if header := ss.header.Get(requestid.MetadataKey); len(header) != 1 || header[0] != got {
+				t.Errorf("wrong header %v, expected - %s", header, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package interceptors
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/propagation"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// UnaryTraceParent returns the W3C trace context of the call span
+// in the traceparent header, the span is started by the otelgrpc stats handler.
+func UnaryTraceParent() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if md := traceParent(ctx); md.Len() > 0 {
+			_ = grpc.SetHeader(ctx, md)
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamTraceParent is UnaryTraceParent of streaming calls.
+func StreamTraceParent() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if md := traceParent(ss.Context()); md.Len() > 0 {
+			_ = ss.SetHeader(md)
+		}
+
+		return handler(srv, ss)
+	}
+}
+
+func traceParent(ctx context.Context) metadata.MD {
+	md := metadata.MD{}
+	propagation.TraceContext{}.Inject(ctx, metadataCarrier(md))
+
+	return md
+}
+
+// metadataCarrier adapts metadata to the propagation.TextMapCarrier interface.
+type metadataCarrier metadata.MD
+
+// Get returns the first value of the key.
+func (c metadataCarrier) Get(key string) string {
+	if values := metadata.MD(c).Get(key); len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
+
+// Set replaces values of the key.
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+// Keys lists the keys stored in the carrier.
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
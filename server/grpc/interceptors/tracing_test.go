@@ -0,0 +1,54 @@
+package interceptors
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+)
+
+func TestStreamTraceParent(t *testing.T) {
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	span := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	})
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want string
+	}{
+		{
+			name: "should return traceparent",
+			ctx:  trace.ContextWithSpanContext(context.Background(), span),
+			want: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		},
+		{
+			name: "should skip call without span",
+			ctx:  context.Background(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ss := &serverStreamMock{ctx: tt.ctx}
+			err := StreamTraceParent()(nil, ss, &grpc.StreamServerInfo{}, func(srv interface{}, stream grpc.ServerStream) error {
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got string
+			if values := ss.header.Get("traceparent"); len(values) > 0 {
+				got = values[0]
+			}
+			if got != tt.want {
+				t.Errorf("wrong traceparent (%s), expected - %s", got, tt.want)
+			}
+		})
+	}
+}
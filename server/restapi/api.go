@@ -2,10 +2,13 @@ package restapi
 
 import (
 	"context"
+	"errors"
 	"net/http"
 
 	"github.com/dolefir/refresh-hash/config"
 	"github.com/dolefir/refresh-hash/logger"
+	"github.com/dolefir/refresh-hash/metrics"
+	"github.com/dolefir/refresh-hash/server/accesslog"
 	hashs "github.com/dolefir/refresh-hash/server/restapi/handlers"
 	"github.com/gin-gonic/gin"
 )
@@ -17,31 +20,141 @@ type RESTAPI struct {
 	cfg         config.APIServer
 	log         logger.Logger
 	srv         http.Server
+	// admin serves admin routes, nil without an admin address.
+	admin     *http.Server
+	metrics   *metrics.Metrics
+	health    *hashs.Health
+	logLevel  *hashs.LogLevel
+	accessLog *accesslog.Policy
+	// service name of request spans, tracing is off when empty
+	tracing string
+}
+
+// Option options for REST API setup.
+type Option func(a *RESTAPI)
+
+// WithMetrics records request metrics and serves them on /metrics.
+func WithMetrics(m *metrics.Metrics) Option {
+	return func(a *RESTAPI) {
+		a.metrics = m
+	}
+}
+
+// WithHealth serves /healthz and /readyz.
+func WithHealth(health *hashs.Health) Option {
+	return func(a *RESTAPI) {
+		a.health = health
+	}
+}
+
+// WithLogLevel serves the runtime log level on /admin/log-level
+// of the admin API.
+func WithLogLevel(logLevel *hashs.LogLevel) Option {
+	return func(a *RESTAPI) {
+		a.logLevel = logLevel
+	}
+}
+
+// WithAccessLog logs requests allowed by the policy.
+func WithAccessLog(policy *accesslog.Policy) Option {
+	return func(a *RESTAPI) {
+		a.accessLog = policy
+	}
+}
+
+// WithTracing starts a span for every request continuing the incoming
+// W3C trace context and returns it in the traceparent header.
+func WithTracing(serviceName string) Option {
+	return func(a *RESTAPI) {
+		a.tracing = serviceName
+	}
 }
 
 // NewAPI returns a new REST API with dependencies.
-func NewAPI(hashHandler *hashs.Handler, cfg config.APIServer, log logger.Logger) *RESTAPI {
+func NewAPI(hashHandler *hashs.Handler, cfg config.APIServer, log logger.Logger, options ...Option) *RESTAPI {
 	api := &RESTAPI{
 		hashHandler: hashHandler,
 		cfg:         cfg,
 		log:         log,
 	}
-	router := gin.Default()
-	api.routes(router)
+	for _, option := range options {
+		option(api)
+	}
 
+	router := api.newRouter()
+	api.routes(router)
 	api.srv = http.Server{
 		Addr:    api.cfg.HTTP.ListenAddr,
 		Handler: router,
 	}
 
+	if api.cfg.HTTP.AdminAddr != "" {
+		admin := api.newRouter()
+		api.adminRoutes(admin)
+		api.admin = &http.Server{
+			Addr:    api.cfg.HTTP.AdminAddr,
+			Handler: admin,
+		}
+	}
+
 	return api
 }
 
+// newRouter returns a router with the middlewares of the API.
+func (a *RESTAPI) newRouter() *gin.Engine {
+	// Everything is logged by the logger, not by gin itself.
+	gin.SetMode(gin.ReleaseMode)
+	router := gin.New()
+	// Handlers pass *gin.Context to services, it must
+	// expose values of the request context.
+	router.ContextWithFallback = true
+	if a.tracing != "" {
+		router.Use(tracingMiddleware(a.tracing)...)
+	}
+	router.Use(requestIDMiddleware(a.log))
+	if a.accessLog != nil {
+		router.Use(accessLogMiddleware(a.log, a.accessLog))
+	}
+	router.Use(recoveryMiddleware(a.log))
+	if a.metrics != nil {
+		router.Use(metricsMiddleware(a.metrics))
+	}
+
+	return router
+}
+
 // ListenAndServe starts an API server.
 func (a *RESTAPI) ListenAndServe(ctx context.Context) error {
 	return a.srv.ListenAndServe()
 }
 
+// ListenAndServeAdmin starts the admin API server,
+// it returns nil at once without an admin address.
+func (a *RESTAPI) ListenAndServeAdmin(ctx context.Context) error {
+	if a.admin == nil {
+		return nil
+	}
+
+	return a.admin.ListenAndServe()
+}
+
+// Shutdown closes open event streams and gracefully stops the servers.
 func (a *RESTAPI) Shutdown(ctx context.Context) error {
-	return a.srv.Shutdown(ctx)
+	a.hashHandler.Close()
+	err := a.srv.Shutdown(ctx)
+	if a.admin != nil {
+		err = errors.Join(err, a.admin.Shutdown(ctx))
+	}
+
+	return err
+}
+
+// Close immediately closes the servers and all their connections.
+func (a *RESTAPI) Close() error {
+	err := a.srv.Close()
+	if a.admin != nil {
+		err = errors.Join(err, a.admin.Close())
+	}
+
+	return err
 }
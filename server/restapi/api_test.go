@@ -0,0 +1,67 @@
+package restapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dolefir/refresh-hash/config"
+	"github.com/dolefir/refresh-hash/logger"
+	hashs "github.com/dolefir/refresh-hash/server/restapi/handlers"
+)
+
+func TestNewAPI_AdminRoutes(t *testing.T) {
+	tests := []struct {
+		name        string
+		adminAddr   string
+		wantPublic  int
+		wantAdmin   int
+		wantNoAdmin bool
+	}{
+		{
+			name:        "should not serve admin routes by default",
+			wantPublic:  http.StatusNotFound,
+			wantNoAdmin: true,
+		},
+		{
+			name:       "should serve admin routes on the admin address only",
+			adminAddr:  "127.0.0.1:8082",
+			wantPublic: http.StatusNotFound,
+			wantAdmin:  http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.NewConfig("")
+			cfg.APIServer.HTTP.AdminAddr = tt.adminAddr
+			log := logger.NewLogger((*logger.CFGLogger)(&cfg.Logger), nil)
+			api := NewAPI(hashs.NewHandler(nil), cfg.APIServer, log,
+				WithLogLevel(hashs.NewLogLevel(logger.NewLevelControl(log))))
+
+			rec := httptest.NewRecorder()
+			api.srv.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/log-level", nil))
+			if rec.Code != tt.wantPublic {
+				t.Errorf("wrong public status %d, expected - %d", rec.Code, tt.wantPublic)
+			}
+			for _, path := range []string{"/api/hash/refresh", "/api/hash/csrf/refresh"} {
+				rec = httptest.NewRecorder()
+				api.srv.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, nil))
+				if rec.Code != tt.wantPublic {
+					t.Errorf("wrong public status %d of %s, expected - %d", rec.Code, path, tt.wantPublic)
+				}
+			}
+
+			if tt.wantNoAdmin {
+				if api.admin != nil {
+					t.Error("admin server is set without an admin address")
+				}
+				return
+			}
+			rec = httptest.NewRecorder()
+			api.admin.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/log-level", nil))
+			if rec.Code != tt.wantAdmin {
+				t.Errorf("wrong admin status %d, expected - %d", rec.Code, tt.wantAdmin)
+			}
+		})
+	}
+}
@@ -1,7 +1,11 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"sync"
+	"time"
 
 	"github.com/dolefir/refresh-hash/models"
 	"github.com/dolefir/refresh-hash/services"
@@ -10,23 +14,129 @@ import (
 
 // Handler holds all actions for hash.
 type Handler struct {
-	hashSrv services.Hash
+	hashSrv   services.Hash
+	done      chan struct{}
+	closeOnce *sync.Once
+	// heartbeat is the interval of comments sent on idle event streams.
+	heartbeat time.Duration
 }
 
 // NewHandler return a new handler.
 func NewHandler(hash services.Hash) *Handler {
 	return &Handler{
-		hashSrv: hash,
+		hashSrv:   hash,
+		done:      make(chan struct{}),
+		closeOnce: new(sync.Once),
+		heartbeat: heartbeatInterval,
 	}
 }
 
-// Get - handler GET for /api/hash endpoint.
+// Close ends all open event streams, otherwise
+// the server shutdown waits for them until its deadline.
+func (h Handler) Close() {
+	h.closeOnce.Do(func() {
+		close(h.done)
+	})
+}
+
+// Get - handler GET for /api/hash and /api/hash/:name endpoints.
 func (h Handler) Get(ctx *gin.Context) {
-	hash, err := h.hashSrv.Get(ctx)
+	hash, err := h.hashSrv.Get(ctx, namespace(ctx))
 	if err != nil {
-		ctx.AbortWithStatus(http.StatusInternalServerError)
+		abortWithError(ctx, err)
+		return
+	}
+
+	// The hash is a per-client secret, shared caches must not keep it.
+	if hash.ExpiresAt != nil && hash.TTL != nil {
+		ctx.Header("Cache-Control", fmt.Sprintf("private, max-age=%d", *hash.TTL))
+		ctx.Header("Expires", hash.ExpiresAt.UTC().Format(http.TimeFormat))
+	} else {
+		ctx.Header("Cache-Control", "no-store")
+	}
+	ctx.JSON(http.StatusOK, hash)
+}
+
+// Refresh - handler POST for /api/hash/refresh endpoint.
+// It rotates the hash immediately and returns the new one.
+func (h Handler) Refresh(ctx *gin.Context) {
+	name := namespace(ctx)
+	hash, err := h.hashSrv.Refresh(ctx, name)
+	if err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, hash)
+}
+
+// History - handler GET for /api/hash/history endpoint.
+// With the optional "at" query (RFC 3339) it returns
+// the single hash that was active at that time.
+func (h Handler) History(ctx *gin.Context) {
+	if at := ctx.Query("at"); at != "" {
+		t, err := time.Parse(time.RFC3339, at)
+		if err != nil {
+			ctx.AbortWithStatus(http.StatusBadRequest)
+			return
+		}
+
+		hash, err := h.hashSrv.ActiveAt(ctx, namespace(ctx), t)
+		if err != nil {
+			abortWithError(ctx, err)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, hash)
+		return
+	}
+
+	history, err := h.hashSrv.History(ctx, namespace(ctx))
+	if err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, history)
+}
+
+// validateRequest is the body of POST /api/hash/validate.
+type validateRequest struct {
+	ID string `json:"uuid" binding:"required"`
+}
+
+// Validate - handler POST for /api/hash/validate endpoint.
+func (h Handler) Validate(ctx *gin.Context) {
+	var req validateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+
+	validation, err := h.hashSrv.Validate(ctx, namespace(ctx), req.ID)
+	if err != nil {
+		abortWithError(ctx, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, models.Hash{ID: hash.ID, Datatime: hash.Datatime})
+	ctx.JSON(http.StatusOK, validation)
+}
+
+// namespace returns the namespace from the :name path
+// parameter, routes without it address the default one.
+func namespace(ctx *gin.Context) string {
+	if name := ctx.Param("name"); name != "" {
+		return name
+	}
+
+	return models.DefaultNamespace
+}
+
+func abortWithError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrNamespaceNotFound), errors.Is(err, services.ErrHashNotFound):
+		ctx.AbortWithStatus(http.StatusNotFound)
+	default:
+		ctx.AbortWithStatus(http.StatusInternalServerError)
+	}
 }
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dolefir/refresh-hash/models"
+	"github.com/gin-gonic/gin"
+)
+
+func TestHandler_Get(t *testing.T) {
+	expiresAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	zero := int64(0)
+	tests := []struct {
+		name             string
+		hash             models.Hash
+		wantCacheControl string
+		wantTTL          bool
+	}{
+		{
+			name:             "should be cached privately until expiry",
+			hash:             models.Hash{ID: "hash-1", ExpiresAt: &expiresAt, TTL: &zero},
+			wantCacheControl: "private, max-age=0",
+			wantTTL:          true,
+		},
+		{
+			name:             "should not be stored without expiry",
+			hash:             models.Hash{ID: "hash-1"},
+			wantCacheControl: "no-store",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.GET("/api/hash", NewHandler(hashServiceMock{history: []models.Hash{tt.hash}}).Get)
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/hash", nil))
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("wrong status %d", rec.Code)
+			}
+			if got := rec.Header().Get("Cache-Control"); got != tt.wantCacheControl {
+				t.Errorf("wrong Cache-Control %q, expected - %q", got, tt.wantCacheControl)
+			}
+			if got := strings.Contains(rec.Body.String(), `"ttl":0`); got != tt.wantTTL {
+				t.Errorf("ttl in %s is %v, expected - %v", rec.Body, got, tt.wantTTL)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Readiness reports whether the application is ready to serve.
+type Readiness interface {
+	Ready() error
+}
+
+// Health holds liveness and readiness actions.
+type Health struct {
+	readiness Readiness
+}
+
+// NewHealth return a new health handler.
+func NewHealth(readiness Readiness) *Health {
+	return &Health{
+		readiness: readiness,
+	}
+}
+
+// Live - handler GET for /healthz endpoint.
+func (h Health) Live(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// Ready - handler GET for /readyz endpoint.
+func (h Health) Ready(ctx *gin.Context) {
+	if err := h.readiness.Ready(); err != nil {
+		ctx.JSON(http.StatusServiceUnavailable, gin.H{"status": "not ready", "error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
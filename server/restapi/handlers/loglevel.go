@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/dolefir/refresh-hash/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// LevelControl reads and changes the log level at runtime.
+type LevelControl interface {
+	Get() logger.LevelState
+	Set(level string, revertAfter time.Duration) (logger.LevelState, error)
+}
+
+// LogLevel holds actions for the runtime log level.
+type LogLevel struct {
+	control LevelControl
+}
+
+// NewLogLevel return a new log level handler.
+func NewLogLevel(control LevelControl) *LogLevel {
+	return &LogLevel{
+		control: control,
+	}
+}
+
+// logLevelRequest is the body of PUT /admin/log-level,
+// revert_after is a duration like 10m, empty keeps the level.
+type logLevelRequest struct {
+	Level       string `json:"level" binding:"required"`
+	RevertAfter string `json:"revert_after"`
+}
+
+// Get - handler GET for /admin/log-level endpoint.
+func (h LogLevel) Get(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, h.control.Get())
+}
+
+// Set - handler PUT for /admin/log-level endpoint.
+func (h LogLevel) Set(ctx *gin.Context) {
+	var req logLevelRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+
+	var revertAfter time.Duration
+	if req.RevertAfter != "" {
+		d, err := time.ParseDuration(req.RevertAfter)
+		if err != nil || d < 0 {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid revert_after"})
+			return
+		}
+		revertAfter = d
+	}
+
+	state, err := h.control.Set(req.Level, revertAfter)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, state)
+}
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dolefir/refresh-hash/config"
+	"github.com/dolefir/refresh-hash/logger"
+	"github.com/gin-gonic/gin"
+)
+
+func TestLogLevel(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		body       string
+		wantStatus int
+		wantLevel  string
+		wantRevert bool
+	}{
+		{
+			name:       "should return the level",
+			method:     http.MethodGet,
+			wantStatus: http.StatusOK,
+			wantLevel:  "info",
+		},
+		{
+			name:       "should set the level",
+			method:     http.MethodPut,
+			body:       `{"level": "debug"}`,
+			wantStatus: http.StatusOK,
+			wantLevel:  "debug",
+		},
+		{
+			name:       "should set a temporary level",
+			method:     http.MethodPut,
+			body:       `{"level": "warn", "revert_after": "10m"}`,
+			wantStatus: http.StatusOK,
+			wantLevel:  "warn",
+			wantRevert: true,
+		},
+		{
+			name:       "should reject an unknown level",
+			method:     http.MethodPut,
+			body:       `{"level": "verbose"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "should reject a negative revert_after",
+			method:     http.MethodPut,
+			body:       `{"level": "debug", "revert_after": "-1m"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "should reject a missing level",
+			method:     http.MethodPut,
+			body:       `{}`,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.NewConfig("")
+			cfg.Logger.LogLevel = "info"
+			log := logger.NewLogger((*logger.CFGLogger)(&cfg.Logger), nil)
+			h := NewLogLevel(logger.NewLevelControl(log))
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.GET("/admin/log-level", h.Get)
+			router.PUT("/admin/log-level", h.Set)
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(tt.method, "/admin/log-level", strings.NewReader(tt.body)))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("wrong status %d, expected - %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				if log.Level() != "info" {
+					t.Errorf("level changed to %s", log.Level())
+				}
+				return
+			}
+
+			var state logger.LevelState
+			if err := json.Unmarshal(rec.Body.Bytes(), &state); err != nil {
+				t.Fatal(err)
+			}
+			if state.Level != tt.wantLevel || log.Level() != tt.wantLevel {
+				t.Errorf("wrong level %s, logger - %s, expected - %s", state.Level, log.Level(), tt.wantLevel)
+			}
+			if got := state.RevertAt != nil; got != tt.wantRevert {
+				t.Errorf("revert_at set is %v, expected - %v", got, tt.wantRevert)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/dolefir/refresh-hash/models"
+	"github.com/gin-gonic/gin"
+)
+
+// heartbeatInterval keeps idle SSE connections
+// from being cut by proxies.
+const heartbeatInterval = 15 * time.Second
+
+// Stream - handler GET for /api/hash/stream endpoint.
+// It pushes a Server-Sent Event for every rotation, the event
+// id is the hash ID, so a reconnecting client sending
+// Last-Event-ID gets the retained hashes it has missed.
+func (h Handler) Stream(ctx *gin.Context) {
+	name := namespace(ctx)
+	// Subscribe before reading the history to not miss a rotation.
+	hashes, err := h.hashSrv.Watch(ctx.Request.Context(), name)
+	if err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+
+	lastID := ctx.GetHeader("Last-Event-ID")
+	backlog, err := h.backlog(ctx, name, lastID)
+	if err != nil {
+		abortWithError(ctx, err)
+		return
+	}
+
+	header := ctx.Writer.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	header.Set("X-Accel-Buffering", "no")
+	ctx.Status(http.StatusOK)
+
+	// An up to date client must not get the current hash again.
+	last := lastID
+	for i := range backlog {
+		if err := writeEvent(ctx.Writer, backlog[i]); err != nil {
+			return
+		}
+		last = backlog[i].ID
+	}
+	ctx.Writer.Flush()
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case hash, ok := <-hashes:
+			if !ok {
+				return
+			}
+			if hash.ID == last {
+				continue
+			}
+			if err := writeEvent(ctx.Writer, hash); err != nil {
+				return
+			}
+			last = hash.ID
+
+		case <-heartbeat.C:
+			if _, err := io.WriteString(ctx.Writer, ": heartbeat\n\n"); err != nil {
+				return
+			}
+
+		case <-h.done:
+			return
+		}
+		ctx.Writer.Flush()
+	}
+}
+
+// backlog returns hashes to send on connect, oldest first.
+// Without lastID or when it is no longer retained
+// only the current hash is sent.
+func (h Handler) backlog(ctx *gin.Context, name, lastID string) ([]models.Hash, error) {
+	if lastID != "" {
+		history, err := h.hashSrv.History(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		for i := range history {
+			if history[i].ID != lastID {
+				continue
+			}
+			missed := make([]models.Hash, 0, i)
+			for j := i - 1; j >= 0; j-- {
+				missed = append(missed, history[j])
+			}
+			return missed, nil
+		}
+	}
+
+	hash, err := h.hashSrv.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return []models.Hash{*hash}, nil
+}
+
+func writeEvent(w io.Writer, hash models.Hash) error {
+	data, err := json.Marshal(hash)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "id: %s\nevent: hash\ndata: %s\n\n", hash.ID, data)
+
+	return err
+}
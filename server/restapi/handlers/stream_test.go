@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dolefir/refresh-hash/models"
+	"github.com/dolefir/refresh-hash/services"
+	"github.com/gin-gonic/gin"
+)
+
+// hashServiceMock serves a fixed history, newest first,
+// and streams hashes sent to rotations.
+type hashServiceMock struct {
+	services.Hash
+	history   []models.Hash
+	rotations chan models.Hash
+}
+
+func (m hashServiceMock) Get(ctx context.Context, namespace string) (*models.Hash, error) {
+	hash := m.history[0]
+	return &hash, nil
+}
+
+func (m hashServiceMock) History(ctx context.Context, namespace string) ([]models.Hash, error) {
+	return m.history, nil
+}
+
+func (m hashServiceMock) Watch(ctx context.Context, namespace string) (<-chan models.Hash, error) {
+	return m.rotations, nil
+}
+
+func newStreamServer(t *testing.T, h *Handler) *httptest.Server {
+	t.Helper()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/hash/stream", h.Stream)
+	srv := httptest.NewServer(router)
+	t.Cleanup(func() {
+		h.Close()
+		srv.Close()
+	})
+
+	return srv
+}
+
+// openStream returns a reader of the event stream.
+func openStream(t *testing.T, url, lastEventID string) *bufio.Reader {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodGet, url+"/api/hash/stream", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	if got := resp.Header.Get("Content-Type"); got != "text/event-stream" {
+		t.Fatalf("wrong content type %q", got)
+	}
+
+	return bufio.NewReader(resp.Body)
+}
+
+// readEvent returns the id of the next event, "heartbeat" for a heartbeat
+// comment and "" once the stream is closed.
+func readEvent(t *testing.T, r *bufio.Reader) string {
+	t.Helper()
+
+	var id string
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return ""
+		}
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case line == "":
+			return id
+		case line == ": heartbeat":
+			id = "heartbeat"
+		case strings.HasPrefix(line, "id: "):
+			id = strings.TrimPrefix(line, "id: ")
+		}
+	}
+}
+
+func hashes(ids ...string) []models.Hash {
+	out := make([]models.Hash, 0, len(ids))
+	for _, id := range ids {
+		out = append(out, models.Hash{ID: id, Namespace: models.DefaultNamespace})
+	}
+
+	return out
+}
+
+func TestHandler_StreamBacklog(t *testing.T) {
+	tests := []struct {
+		name        string
+		lastEventID string
+		want        []string
+	}{
+		{
+			name: "should send the current hash without Last-Event-ID",
+			want: []string{"hash-3"},
+		},
+		{
+			name:        "should replay missed hashes oldest first",
+			lastEventID: "hash-1",
+			want:        []string{"hash-2", "hash-3"},
+		},
+		{
+			name:        "should send the current hash for an unknown Last-Event-ID",
+			lastEventID: "hash-0",
+			want:        []string{"hash-3"},
+		},
+		{
+			name:        "should send nothing to an up to date client",
+			lastEventID: "hash-3",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rotations := make(chan models.Hash, 2)
+			h := NewHandler(hashServiceMock{history: hashes("hash-3", "hash-2", "hash-1"), rotations: rotations})
+			srv := newStreamServer(t, h)
+			stream := openStream(t, srv.URL, tt.lastEventID)
+
+			// The current hash again is not repeated, the next rotation ends the backlog.
+			rotations <- hashes("hash-3")[0]
+			rotations <- hashes("hash-4")[0]
+
+			var got []string
+			for id := readEvent(t, stream); id != "hash-4"; id = readEvent(t, stream) {
+				if id == "" {
+					t.Fatal("stream closed")
+				}
+				got = append(got, id)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("backlog = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandler_StreamHeartbeat(t *testing.T) {
+	h := NewHandler(hashServiceMock{history: hashes("hash-1"), rotations: make(chan models.Hash)})
+	h.heartbeat = 10 * time.Millisecond
+	srv := newStreamServer(t, h)
+	stream := openStream(t, srv.URL, "")
+
+	if id := readEvent(t, stream); id != "hash-1" {
+		t.Fatalf("first event %q, want hash-1", id)
+	}
+	if id := readEvent(t, stream); id != "heartbeat" {
+		t.Errorf("idle stream event %q, want heartbeat", id)
+	}
+}
+
+func TestHandler_StreamClose(t *testing.T) {
+	h := NewHandler(hashServiceMock{history: hashes("hash-1"), rotations: make(chan models.Hash)})
+	srv := newStreamServer(t, h)
+	stream := openStream(t, srv.URL, "")
+
+	if id := readEvent(t, stream); id != "hash-1" {
+		t.Fatalf("first event %q, want hash-1", id)
+	}
+	h.Close()
+
+	done := make(chan string)
+	go func() { done <- readEvent(t, stream) }()
+	select {
+	case id := <-done:
+		if id != "" {
+			t.Errorf("event %q after Close, want the stream closed", id)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("stream is not closed by Close")
+	}
+}
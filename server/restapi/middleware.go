@@ -0,0 +1,139 @@
+package restapi
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"github.com/dolefir/refresh-hash/logger"
+	"github.com/dolefir/refresh-hash/metrics"
+	"github.com/dolefir/refresh-hash/requestid"
+	"github.com/dolefir/refresh-hash/server/accesslog"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// unmatchedRoute labels requests not matching any route,
+// it keeps the route label cardinality bounded.
+const unmatchedRoute = "unmatched"
+
+// metricsMiddleware records count and latency of every request.
+func metricsMiddleware(m *metrics.Metrics) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		ctx.Next()
+
+		route := ctx.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		m.ObserveHTTP(ctx.Request.Method, route, ctx.Writer.Status(), time.Since(start))
+	}
+}
+
+// untracedPaths are probes not worth a span.
+var untracedPaths = map[string]bool{
+	"/healthz": true,
+	"/readyz":  true,
+	"/metrics": true,
+}
+
+// tracingMiddleware starts a request span and writes
+// its context into the traceparent response header.
+func tracingMiddleware(serviceName string) []gin.HandlerFunc {
+	filter := func(r *http.Request) bool {
+		return !untracedPaths[r.URL.Path]
+	}
+
+	return []gin.HandlerFunc{
+		otelgin.Middleware(serviceName, otelgin.WithFilter(filter)),
+		func(ctx *gin.Context) {
+			propagation.TraceContext{}.Inject(ctx.Request.Context(), propagation.HeaderCarrier(ctx.Writer.Header()))
+			ctx.Next()
+		},
+	}
+}
+
+// requestIDMiddleware takes the request ID from the X-Request-ID
+// header or generates one, puts it into the request context together
+// with a logger of the route and returns it in the response header.
+func requestIDMiddleware(log logger.Logger) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := requestid.Ensure(ctx.GetHeader(requestid.Header))
+		route := ctx.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		reqCtx := requestid.NewContext(ctx.Request.Context(), id)
+		reqCtx = logger.WithContext(reqCtx, log.With("http_method", ctx.Request.Method, "route", route))
+		ctx.Request = ctx.Request.WithContext(reqCtx)
+		ctx.Header(requestid.Header, id)
+		ctx.Next()
+	}
+}
+
+// accessLogMiddleware writes a log line for every request allowed by the policy,
+// the level is warn for client errors and error for server errors.
+func accessLogMiddleware(log logger.Logger, policy *accesslog.Policy) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		ctx.Next()
+
+		route := ctx.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		status := ctx.Writer.Status()
+		if !policy.Allow(route, status >= http.StatusInternalServerError) {
+			return
+		}
+
+		id, _ := requestid.FromContext(ctx.Request.Context())
+		fields := []interface{}{
+			"method", ctx.Request.Method,
+			"route", route,
+			"status", status,
+			"latency", time.Since(start),
+			"bytes", ctx.Writer.Size(),
+			"client_ip", ctx.ClientIP(),
+			requestid.LogField, id,
+		}
+		if span := trace.SpanContextFromContext(ctx.Request.Context()); span.IsValid() {
+			fields = append(fields, "trace_id", span.TraceID().String())
+		}
+		switch {
+		case status >= http.StatusInternalServerError:
+			log.Errorw("http request", fields...)
+		case status >= http.StatusBadRequest:
+			log.Warnw("http request", fields...)
+		default:
+			log.Infow("http request", fields...)
+		}
+	}
+}
+
+// recoveryMiddleware logs a panic of a handler with its stack
+// and responds with 500 instead of crashing the server.
+func recoveryMiddleware(log logger.Logger) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		defer func() {
+			if err := recover(); err != nil {
+				if err == http.ErrAbortHandler {
+					panic(err)
+				}
+				id, _ := requestid.FromContext(ctx.Request.Context())
+				log.Errorw("panic recovered",
+					"error", fmt.Sprint(err),
+					"route", ctx.FullPath(),
+					requestid.LogField, id,
+					"stack", string(debug.Stack()),
+				)
+				ctx.AbortWithStatus(http.StatusInternalServerError)
+			}
+		}()
+		ctx.Next()
+	}
+}
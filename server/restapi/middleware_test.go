@@ -0,0 +1,164 @@
+package restapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dolefir/refresh-hash/config"
+	"github.com/dolefir/refresh-hash/logger"
+	"github.com/dolefir/refresh-hash/requestid"
+	"github.com/dolefir/refresh-hash/server/accesslog"
+	"github.com/gin-gonic/gin"
+)
+
+// logEntry is a log line written with key-value pairs.
+type logEntry struct {
+	level  string
+	msg    string
+	fields map[string]interface{}
+}
+
+// logMock records log lines written with key-value pairs.
+type logMock struct {
+	logger.Logger
+	entries *[]logEntry
+}
+
+func newLogMock() logMock {
+	cfg := config.NewConfig("")
+	return logMock{
+		Logger:  logger.NewLogger((*logger.CFGLogger)(&cfg.Logger), nil),
+		entries: new([]logEntry),
+	}
+}
+
+func (l logMock) record(level, msg string, args []interface{}) {
+	fields := make(map[string]interface{}, len(args)/2)
+	for i := 0; i+1 < len(args); i += 2 {
+		fields[args[i].(string)] = args[i+1]
+	}
+	*l.entries = append(*l.entries, logEntry{level: level, msg: msg, fields: fields})
+}
+
+func (l logMock) Infow(msg string, args ...interface{})  { l.record("info", msg, args) }
+func (l logMock) Warnw(msg string, args ...interface{})  { l.record("warn", msg, args) }
+func (l logMock) Errorw(msg string, args ...interface{}) { l.record("error", msg, args) }
+
+func newMiddlewareRouter(log logger.Logger, policy *accesslog.Policy) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(requestIDMiddleware(log), accessLogMiddleware(log, policy), recoveryMiddleware(log))
+	router.GET("/healthz", func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+	router.GET("/api/hash/:name", func(ctx *gin.Context) { ctx.String(http.StatusNotFound, "missing") })
+	router.GET("/panic", func(ctx *gin.Context) { panic("boom") })
+
+	return router
+}
+
+func TestAccessLogMiddleware(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+		wantLevel  string
+		wantRoute  string
+	}{
+		{
+			name:       "should log the route template",
+			path:       "/api/hash/csrf",
+			wantStatus: http.StatusNotFound,
+			wantLevel:  "warn",
+			wantRoute:  "/api/hash/:name",
+		},
+		{
+			name:       "should log an unmatched route",
+			path:       "/unknown",
+			wantStatus: http.StatusNotFound,
+			wantLevel:  "warn",
+			wantRoute:  unmatchedRoute,
+		},
+		{
+			name:       "should log a recovered panic as error",
+			path:       "/panic",
+			wantStatus: http.StatusInternalServerError,
+			wantLevel:  "error",
+			wantRoute:  "/panic",
+		},
+		{
+			name:       "should skip a route",
+			path:       "/healthz",
+			wantStatus: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := newLogMock()
+			router := newMiddlewareRouter(log, accesslog.NewPolicy(config.AccessLog{Skip: []string{"/healthz"}}))
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.Header.Set(requestid.Header, "req-1")
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("wrong status %d, expected - %d", rec.Code, tt.wantStatus)
+			}
+			var access []logEntry
+			for _, entry := range *log.entries {
+				if entry.msg == "http request" {
+					access = append(access, entry)
+				}
+			}
+			if tt.wantLevel == "" {
+				if len(access) != 0 {
+					t.Fatalf("skipped route logged: %v", access)
+				}
+				return
+			}
+			if len(access) != 1 {
+				t.Fatalf("wrong number of access log lines (%d), expected - 1", len(access))
+			}
+			entry := access[0]
+			if entry.level != tt.wantLevel {
+				t.Errorf("wrong level %s, expected - %s", entry.level, tt.wantLevel)
+			}
+			for _, key := range []string{"method", "route", "status", "latency", "bytes", "client_ip", requestid.LogField} {
+				if _, ok := entry.fields[key]; !ok {
+					t.Errorf("no %s field in %v", key, entry.fields)
+				}
+			}
+			if entry.fields["method"] != http.MethodGet || entry.fields["route"] != tt.wantRoute ||
+				entry.fields["status"] != tt.wantStatus || entry.fields[requestid.LogField] != "req-1" {
+				t.Errorf("wrong fields %v", entry.fields)
+			}
+		})
+	}
+}
+
+func TestRecoveryMiddleware(t *testing.T) {
+	log := newLogMock()
+	router := newMiddlewareRouter(log, accesslog.NewPolicy(config.AccessLog{}))
+
+	req := httptest.NewRequest(http.MethodGet, "/panic", nil)
+	req.Header.Set(requestid.Header, "req-1")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("wrong status %d, expected - %d", rec.Code, http.StatusInternalServerError)
+	}
+	var panics []logEntry
+	for _, entry := range *log.entries {
+		if entry.msg == "panic recovered" {
+			panics = append(panics, entry)
+		}
+	}
+	if len(panics) != 1 {
+		t.Fatalf("wrong number of panic log lines (%d), expected - 1", len(panics))
+	}
+	if entry := panics[0]; entry.level != "error" || entry.fields["error"] != "boom" ||
+		entry.fields["route"] != "/panic" || entry.fields[requestid.LogField] != "req-1" || entry.fields["stack"] == "" {
+		t.Errorf("wrong panic log %v", entry)
+	}
+}
@@ -7,16 +7,54 @@ import (
 )
 
 func (a *RESTAPI) routes(router *gin.Engine) {
-	router.NoRoute(func(ctx *gin.Context) {
-		ctx.AbortWithStatus(http.StatusNotFound)
-	})
-	router.NoMethod(func(ctx *gin.Context) {
-		ctx.AbortWithStatus(http.StatusMethodNotAllowed)
-	})
+	noRoute(router)
+
+	if a.health != nil {
+		router.GET("/healthz", a.health.Live)
+		router.GET("/readyz", a.health.Ready)
+	}
+	if a.metrics != nil {
+		router.GET("/metrics", gin.WrapH(a.metrics.Handler()))
+	}
 
 	api := router.Group("/api/")
 	gHash := api.Group("hash")
 	{
 		gHash.GET("", a.hashHandler.Get)
+		gHash.GET("/history", a.hashHandler.History)
+		gHash.GET("/stream", a.hashHandler.Stream)
+		gHash.POST("/validate", a.hashHandler.Validate)
+	}
+	// Routes of named namespaces, the ones above address the default namespace.
+	gNamespace := gHash.Group("/:name")
+	{
+		gNamespace.GET("", a.hashHandler.Get)
+		gNamespace.GET("/history", a.hashHandler.History)
+		gNamespace.GET("/stream", a.hashHandler.Stream)
+		gNamespace.POST("/validate", a.hashHandler.Validate)
+	}
+}
+
+// adminRoutes are routes changing the service, served on the admin address only.
+func (a *RESTAPI) adminRoutes(router *gin.Engine) {
+	noRoute(router)
+
+	// Rotations on demand invalidate hashes of every client.
+	router.POST("/api/hash/refresh", a.hashHandler.Refresh)
+	router.POST("/api/hash/:name/refresh", a.hashHandler.Refresh)
+
+	if a.logLevel != nil {
+		admin := router.Group("/admin")
+		admin.GET("/log-level", a.logLevel.Get)
+		admin.PUT("/log-level", a.logLevel.Set)
 	}
 }
+
+func noRoute(router *gin.Engine) {
+	router.NoRoute(func(ctx *gin.Context) {
+		ctx.AbortWithStatus(http.StatusNotFound)
+	})
+	router.NoMethod(func(ctx *gin.Context) {
+		ctx.AbortWithStatus(http.StatusMethodNotAllowed)
+	})
+}
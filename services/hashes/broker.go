@@ -0,0 +1,68 @@
+package hashes
+
+import (
+	"sync"
+
+	"github.com/dolefir/refresh-hash/models"
+)
+
+// broker fans out rotated hashes to subscribers.
+// Each subscriber has a single slot buffer, a slow subscriber
+// never blocks the rotation and only misses intermediate
+// hashes, it always receives the latest one.
+type broker struct {
+	mu sync.Mutex
+	// subs maps subscribers to their namespace.
+	subs map[chan models.Hash]string
+}
+
+func newBroker() *broker {
+	return &broker{subs: make(map[chan models.Hash]string)}
+}
+
+func (b *broker) subscribe(namespace string) chan models.Hash {
+	ch := make(chan models.Hash, 1)
+
+	b.mu.Lock()
+	b.subs[ch] = namespace
+	b.mu.Unlock()
+
+	return ch
+}
+
+func (b *broker) unsubscribe(ch chan models.Hash) {
+	b.mu.Lock()
+	delete(b.subs, ch)
+	b.mu.Unlock()
+
+	close(ch)
+}
+
+func (b *broker) publish(h models.Hash) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch, namespace := range b.subs {
+		if namespace != h.Namespace {
+			continue
+		}
+		select {
+		case ch <- h:
+			continue
+		default:
+		}
+		// Drop the stale hash and replace it with the latest one.
+		select {
+		case <-ch:
+		default:
+		}
+		select {
+		case ch <- h:
+		default:
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package hashes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dolefir/refresh-hash/config"
+	"github.com/dolefir/refresh-hash/logger"
+	"github.com/dolefir/refresh-hash/models"
+	"github.com/dolefir/refresh-hash/services/mock"
+)
+
+func TestBroker_SlowSubscriberGetsLatest(t *testing.T) {
+	b := newBroker()
+	ch := b.subscribe(models.DefaultNamespace)
+
+	for _, id := range []string{"1", "2", "3"} {
+		b.publish(models.Hash{ID: id, Namespace: models.DefaultNamespace})
+	}
+
+	if got := <-ch; got.ID != "3" {
+		t.Errorf("broker.publish() delivered %v, want latest hash 3", got.ID)
+	}
+	select {
+	case got := <-ch:
+		t.Errorf("unexpected extra hash %v", got.ID)
+	default:
+	}
+}
+
+func TestService_Watch(t *testing.T) {
+	cfg := config.NewConfig("")
+	log := logger.NewLogger((*logger.CFGLogger)(&cfg.Logger), nil)
+	s := NewService(mock.NewInmemMock(), log)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	hashes, err := s.Watch(ctx, models.DefaultNamespace)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.Refresh(ctx, models.DefaultNamespace); err != nil {
+		t.Fatal(err)
+	}
+	if got := <-hashes; got.ID == "" {
+		t.Error("Service.Watch() delivered an empty hash")
+	}
+
+	cancel()
+	for range hashes {
+	}
+}
@@ -2,63 +2,329 @@ package hashes
 
 import (
 	"context"
+	"errors"
 	"time"
 
+	"github.com/dolefir/refresh-hash/clock"
+	"github.com/dolefir/refresh-hash/generator"
 	"github.com/dolefir/refresh-hash/logger"
 	"github.com/dolefir/refresh-hash/models"
 	"github.com/dolefir/refresh-hash/repository"
-	"github.com/google/uuid"
+	"github.com/dolefir/refresh-hash/services"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// component is the log field value of the service.
+const component = "hash-service"
+
 // Service handle common for hash operations.
 type Service struct {
-	hashRepo repository.Inmem
-	log      logger.Logger
+	hashRepo   repository.Inmem
+	log        logger.Logger
+	grace      time.Duration
+	broker     *broker
+	generator  generator.Generator
+	namespaces map[string]struct{}
+	clock      clock.Clock
+	scheduler  Scheduler
+	recorder   Recorder
+	// tracerProvider is the source of service spans, the global one by default.
+	tracerProvider trace.TracerProvider
+}
+
+// Recorder records metrics of hash refreshes.
+type Recorder interface {
+	ObserveRefresh(namespace string, duration time.Duration, err error)
+}
+
+// Scheduler reports when a namespace hash is rotated next.
+type Scheduler interface {
+	NextRotation(namespace string) (time.Time, bool)
+}
+
+// Option options for service setup.
+type Option func(s *Service)
+
+// WithGracePeriod keeps the previous hash valid
+// for the given duration after rotation.
+func WithGracePeriod(grace time.Duration) Option {
+	return func(s *Service) {
+		s.grace = grace
+	}
+}
+
+// WithGenerator sets the generator of new hashes, uuid4 by default.
+func WithGenerator(g generator.Generator) Option {
+	return func(s *Service) {
+		s.generator = g
+	}
+}
+
+// WithNamespaces declares namespaces served in addition to the default one.
+func WithNamespaces(names ...string) Option {
+	return func(s *Service) {
+		s.namespaces = make(map[string]struct{}, len(names))
+		for _, name := range names {
+			s.namespaces[name] = struct{}{}
+		}
+	}
+}
+
+// WithClock sets the clock, the real one by default.
+func WithClock(c clock.Clock) Option {
+	return func(s *Service) {
+		s.clock = c
+	}
+}
+
+// WithScheduler sets the source of the expiry of the current hash.
+func WithScheduler(scheduler Scheduler) Option {
+	return func(s *Service) {
+		s.scheduler = scheduler
+	}
+}
+
+// WithRecorder records metrics of every refresh.
+func WithRecorder(recorder Recorder) Option {
+	return func(s *Service) {
+		s.recorder = recorder
+	}
+}
+
+// WithTracerProvider sets the provider of service spans,
+// the global one by default.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(s *Service) {
+		s.tracerProvider = provider
+	}
 }
 
 // NewService creates new hash service.
-func NewService(hashRepo repository.Inmem, log logger.Logger) *Service {
-	return &Service{
+func NewService(hashRepo repository.Inmem, log logger.Logger, options ...Option) *Service {
+	s := &Service{
 		hashRepo: hashRepo,
 		log:      log,
+		broker:   newBroker(),
 	}
+	for _, option := range options {
+		option(s)
+	}
+
+	return s
 }
 
-// Get returns hash.
-func (s Service) Get(ctx context.Context) (*models.Hash, error) {
-	s.log.Debug("service.Hash.Get: get hash")
-	hash, err := s.hashRepo.Get()
+// Get returns hash of the namespace.
+func (s Service) Get(ctx context.Context, namespace string) (_ *models.Hash, err error) {
+	ctx, span := s.startSpan(ctx, "service.Hash.Get", namespace)
+	defer func() { endSpan(span, err) }()
+
+	ctx = logger.ContextWithNamespace(ctx, namespace)
+	log := s.logger(ctx)
+	log.DebugfCtx(ctx, "get hash")
+	if err := s.checkNamespace(namespace); err != nil {
+		return nil, err
+	}
+
+	hash, err := s.getHash(ctx, namespace)
 	if err != nil {
-		s.log.Errorf("service.Hash.Get: %s", err)
+		log.ErrorfCtx(ctx, "get hash: %s", err)
 		return nil, err
 	}
 	if hash.ID == "" {
 		// Create a new hash if not exist.
-		if err := s.Refresh(ctx); err != nil {
-			return nil, err
-		}
+		return s.Refresh(ctx, namespace)
 	}
 
-	s.log.Debugf("service.Hash.Get: hash exist %s", hash)
+	log.DebugfCtx(ctx, "hash exist %s", hash.ID)
 
-	return hash, nil
+	return s.withExpiry(namespace, *hash), nil
 }
 
-// Refresh to create/update hash inmem.
-func (s Service) Refresh(ctx context.Context) error {
-	s.log.Debug("service.Hash.Refresh: refresh hash")
+// Refresh to create/update hash of the namespace inmem,
+// it returns the new hash.
+func (s Service) Refresh(ctx context.Context, namespace string) (_ *models.Hash, err error) {
+	ctx, span := s.startSpan(ctx, "service.Hash.Refresh", namespace)
+	defer func() { endSpan(span, err) }()
+
+	ctx = logger.ContextWithNamespace(ctx, namespace)
+	log := s.logger(ctx)
+	log.DebugfCtx(ctx, "refresh hash")
+	if err := s.checkNamespace(namespace); err != nil {
+		return nil, err
+	}
+
+	if s.recorder != nil {
+		start := s.now()
+		defer func() {
+			s.recorder.ObserveRefresh(namespace, s.now().Sub(start), err)
+		}()
+	}
+
+	gen := s.generator
+	if gen == nil {
+		gen = generator.UUIDv4{}
+	}
+
+	id, err := gen.Generate()
+	if err != nil {
+		log.ErrorfCtx(ctx, "refresh hash: %s", err)
+		return nil, err
+	}
 
 	hash := &models.Hash{
-		ID:       uuid.New().String(),
-		Datatime: time.Now(),
+		ID:        id,
+		Datatime:  s.now(),
+		Generator: gen.Type(),
+		Namespace: namespace,
 	}
 
-	if err := s.hashRepo.Set(hash); err != nil {
-		s.log.Errorf("service.Hash.Refresh: %s", err)
-		return err
+	if err := s.setHash(ctx, hash); err != nil {
+		log.ErrorfCtx(ctx, "refresh hash: %s", err)
+		return nil, err
 	}
 
-	s.log.Debug("service.Hash.Refresh: hash updated")
+	current := s.withExpiry(namespace, *hash)
+	s.broker.publish(*current)
+
+	log.DebugfCtx(ctx, "hash updated")
+
+	return current, nil
+}
+
+// History returns retained hashes of the namespace, newest first.
+func (s Service) History(ctx context.Context, namespace string) ([]models.Hash, error) {
+	ctx = logger.ContextWithNamespace(ctx, namespace)
+	log := s.logger(ctx)
+	log.DebugfCtx(ctx, "list hashes")
+	if err := s.checkNamespace(namespace); err != nil {
+		return nil, err
+	}
+
+	history, err := s.listHashes(ctx, namespace)
+	if err != nil {
+		log.ErrorfCtx(ctx, "list hashes: %s", err)
+		return nil, err
+	}
+
+	return history, nil
+}
+
+// ActiveAt returns the hash that was active at the given time.
+func (s Service) ActiveAt(ctx context.Context, namespace string, at time.Time) (*models.Hash, error) {
+	ctx = logger.ContextWithNamespace(ctx, namespace)
+	s.logger(ctx).DebugfCtx(ctx, "find hash at %s", at)
+	history, err := s.History(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	// History is newest first, so the first hash
+	// created not after the given time is the active one.
+	for i := range history {
+		if !history[i].Datatime.After(at) {
+			return &history[i], nil
+		}
+	}
+
+	return nil, services.ErrHashNotFound
+}
+
+// Validate reports whether the hash is the current one
+// or the previous one still within the grace period.
+func (s Service) Validate(ctx context.Context, namespace, id string) (*models.Validation, error) {
+	ctx = logger.ContextWithNamespace(ctx, namespace)
+	s.logger(ctx).DebugfCtx(ctx, "validate hash")
+	history, err := s.History(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case id == "" || len(history) == 0:
+	case history[0].ID == id:
+		return &models.Validation{Valid: true, State: models.HashStateCurrent}, nil
+	case len(history) > 1 && history[1].ID == id:
+		validUntil := history[0].Datatime.Add(s.grace)
+		if s.now().Before(validUntil) {
+			return &models.Validation{
+				Valid:      true,
+				State:      models.HashStateGrace,
+				ValidUntil: &validUntil,
+			}, nil
+		}
+	}
+
+	return &models.Validation{Valid: false, State: models.HashStateInvalid}, nil
+}
+
+// Watch returns a channel receiving every rotated hash
+// of the namespace until ctx is done, then the channel
+// is closed. A slow reader only gets the latest hash.
+func (s Service) Watch(ctx context.Context, namespace string) (<-chan models.Hash, error) {
+	ctx = logger.ContextWithNamespace(ctx, namespace)
+	log := s.logger(ctx)
+	log.DebugfCtx(ctx, "subscribe")
+	if s.broker == nil {
+		return nil, errors.New("service.Hash.Watch: service is not initialized")
+	}
+	if err := s.checkNamespace(namespace); err != nil {
+		return nil, err
+	}
+
+	ch := s.broker.subscribe(namespace)
+	go func() {
+		<-ctx.Done()
+		s.broker.unsubscribe(ch)
+		log.DebugfCtx(ctx, "unsubscribe")
+	}()
+
+	return ch, nil
+}
+
+// logger returns the logger of ctx, the service one by default.
+func (s Service) logger(ctx context.Context) logger.Logger {
+	return logger.FromContext(ctx, s.log).With(logger.ComponentField, component)
+}
+
+// withExpiry returns a copy of the current hash
+// with the next rotation time and remaining TTL.
+func (s Service) withExpiry(namespace string, hash models.Hash) *models.Hash {
+	if s.scheduler == nil {
+		return &hash
+	}
+
+	expiresAt, ok := s.scheduler.NextRotation(namespace)
+	if !ok {
+		return &hash
+	}
+
+	ttl := expiresAt.Sub(s.now())
+	if ttl < 0 {
+		ttl = 0
+	}
+	hash.ExpiresAt = &expiresAt
+	// Truncate, a client caching for TTL seconds must not outlive the hash.
+	seconds := int64(ttl / time.Second)
+	hash.TTL = &seconds
+
+	return &hash
+}
+
+func (s Service) now() time.Time {
+	if s.clock == nil {
+		return time.Now()
+	}
+
+	return s.clock.Now()
+}
+
+func (s Service) checkNamespace(namespace string) error {
+	if namespace == models.DefaultNamespace {
+		return nil
+	}
+	if _, ok := s.namespaces[namespace]; !ok {
+		return services.ErrNamespaceNotFound
+	}
 
 	return nil
 }
@@ -2,13 +2,17 @@ package hashes
 
 import (
 	"context"
+	"errors"
 	"reflect"
 	"testing"
+	"time"
 
+	"github.com/dolefir/refresh-hash/clock"
 	"github.com/dolefir/refresh-hash/config"
 	"github.com/dolefir/refresh-hash/logger"
 	"github.com/dolefir/refresh-hash/models"
 	"github.com/dolefir/refresh-hash/repository"
+	"github.com/dolefir/refresh-hash/services"
 	"github.com/dolefir/refresh-hash/services/mock"
 )
 
@@ -49,7 +53,7 @@ func TestService_Get(t *testing.T) {
 				hashRepo: tt.fields.hashRepo,
 				log:      log,
 			}
-			got, err := s.Get(tt.args.ctx)
+			got, err := s.Get(tt.args.ctx, models.DefaultNamespace)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Service.Get() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -96,7 +100,7 @@ func TestService_GetWithError(t *testing.T) {
 				hashRepo: tt.fields.hashRepo,
 				log:      log,
 			}
-			got, err := s.Get(tt.args.ctx)
+			got, err := s.Get(tt.args.ctx, models.DefaultNamespace)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Service.Get() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -141,8 +145,13 @@ func TestService_Refresh(t *testing.T) {
 				hashRepo: tt.fields.hashRepo,
 				log:      log,
 			}
-			if err := s.Refresh(tt.args.ctx); (err != nil) != tt.wantErr {
+			got, err := s.Refresh(tt.args.ctx, models.DefaultNamespace)
+			if (err != nil) != tt.wantErr {
 				t.Errorf("Service.Refresh() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got == nil || got.ID == "" || got.Namespace != models.DefaultNamespace {
+				t.Errorf("Service.Refresh() = %v, expected the new hash", got)
 			}
 		})
 	}
@@ -181,9 +190,214 @@ func TestService_RefreshWithError(t *testing.T) {
 				hashRepo: tt.fields.hashRepo,
 				log:      log,
 			}
-			if err := s.Refresh(tt.args.ctx); (err != nil) != tt.wantErr {
+			if _, err := s.Refresh(tt.args.ctx, models.DefaultNamespace); (err != nil) != tt.wantErr {
 				t.Errorf("Service.Refresh() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
 }
+
+func TestService_ActiveAt(t *testing.T) {
+	mockInmem := mock.NewInmemMock()
+	type args struct {
+		ctx context.Context
+		at  time.Time
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "should returns current hash",
+			args: args{
+				ctx: context.Background(),
+				at:  time.Date(2024, 1, 2, 3, 15, 0, 0, time.UTC),
+			},
+			want: "996f2357-31af-4b1a-9889-a075be3de0a9",
+		},
+		{
+			name: "should returns previous hash",
+			args: args{
+				ctx: context.Background(),
+				at:  time.Date(2024, 1, 2, 3, 7, 0, 0, time.UTC),
+			},
+			want: "0b9bcb8e-4e3b-4d41-9a3c-1b0f3b9f6f11",
+		},
+		{
+			name: "should returns error before retained history",
+			args: args{
+				ctx: context.Background(),
+				at:  time.Date(2024, 1, 2, 3, 0, 0, 0, time.UTC),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.NewConfig("")
+			log := logger.NewLogger((*logger.CFGLogger)(&cfg.Logger), nil)
+			s := Service{
+				hashRepo: mockInmem,
+				log:      log,
+			}
+			got, err := s.ActiveAt(tt.args.ctx, models.DefaultNamespace, tt.args.at)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Service.ActiveAt() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != nil && got.ID != tt.want {
+				t.Errorf("Service.ActiveAt() = %v, want %v", got.ID, tt.want)
+			}
+		})
+	}
+}
+
+func TestService_Validate(t *testing.T) {
+	mockInmem := mock.NewInmemMock()
+	rotatedAt := time.Date(2024, 1, 2, 3, 10, 0, 0, time.UTC)
+	type args struct {
+		ctx context.Context
+		id  string
+	}
+	tests := []struct {
+		name string
+		now  time.Time
+		args args
+		want string
+	}{
+		{
+			name: "should returns current state",
+			now:  rotatedAt.Add(time.Hour),
+			args: args{
+				ctx: context.Background(),
+				id:  "996f2357-31af-4b1a-9889-a075be3de0a9",
+			},
+			want: models.HashStateCurrent,
+		},
+		{
+			name: "should returns grace state within grace period",
+			now:  rotatedAt.Add(30 * time.Second),
+			args: args{
+				ctx: context.Background(),
+				id:  "0b9bcb8e-4e3b-4d41-9a3c-1b0f3b9f6f11",
+			},
+			want: models.HashStateGrace,
+		},
+		{
+			name: "should returns invalid state after grace period",
+			now:  rotatedAt.Add(time.Minute),
+			args: args{
+				ctx: context.Background(),
+				id:  "0b9bcb8e-4e3b-4d41-9a3c-1b0f3b9f6f11",
+			},
+			want: models.HashStateInvalid,
+		},
+		{
+			name: "should returns invalid state for unknown hash",
+			now:  rotatedAt,
+			args: args{
+				ctx: context.Background(),
+				id:  "unknown",
+			},
+			want: models.HashStateInvalid,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.NewConfig("")
+			log := logger.NewLogger((*logger.CFGLogger)(&cfg.Logger), nil)
+			s := NewService(mockInmem, log, WithGracePeriod(time.Minute), WithClock(clock.NewFake(tt.now)))
+			got, err := s.Validate(tt.args.ctx, models.DefaultNamespace, tt.args.id)
+			if err != nil {
+				t.Fatalf("Service.Validate() error = %v", err)
+			}
+			if got.State != tt.want || got.Valid != (tt.want != models.HashStateInvalid) {
+				t.Errorf("Service.Validate() = %+v, want state %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestService_Namespace(t *testing.T) {
+	tests := []struct {
+		name      string
+		namespace string
+		wantErr   error
+	}{
+		{
+			name:      "should returns hash of the default namespace",
+			namespace: models.DefaultNamespace,
+		},
+		{
+			name:      "should returns hash of a declared namespace",
+			namespace: "csrf",
+		},
+		{
+			name:      "should returns error for an unknown namespace",
+			namespace: "unknown",
+			wantErr:   services.ErrNamespaceNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.NewConfig("")
+			log := logger.NewLogger((*logger.CFGLogger)(&cfg.Logger), nil)
+			s := NewService(mock.NewInmemMock(), log, WithNamespaces("csrf"))
+			if _, err := s.Get(context.Background(), tt.namespace); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Service.Get() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+type schedulerMock func(namespace string) (time.Time, bool)
+
+func (s schedulerMock) NextRotation(namespace string) (time.Time, bool) {
+	return s(namespace)
+}
+
+func TestService_GetExpiry(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 10, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		next    time.Time
+		ok      bool
+		wantTTL *int64
+	}{
+		{
+			name:    "should returns expiry and ttl",
+			next:    now.Add(90*time.Second + 500*time.Millisecond),
+			ok:      true,
+			wantTTL: func() *int64 { ttl := int64(90); return &ttl }(),
+		},
+		{
+			name: "should returns no expiry without schedule",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.NewConfig("")
+			log := logger.NewLogger((*logger.CFGLogger)(&cfg.Logger), nil)
+			scheduler := schedulerMock(func(namespace string) (time.Time, bool) {
+				return tt.next, tt.ok
+			})
+			s := NewService(mock.NewInmemMock(), log, WithClock(clock.NewFake(now)), WithScheduler(scheduler))
+
+			got, err := s.Get(context.Background(), models.DefaultNamespace)
+			if err != nil {
+				t.Fatalf("Service.Get() error = %v", err)
+			}
+			if (got.ExpiresAt != nil) != tt.ok {
+				t.Fatalf("Service.Get() ExpiresAt = %v, want set %v", got.ExpiresAt, tt.ok)
+			}
+			if got.ExpiresAt != nil && !got.ExpiresAt.Equal(tt.next) {
+				t.Errorf("Service.Get() ExpiresAt = %v, want %v", got.ExpiresAt, tt.next)
+			}
+			if !reflect.DeepEqual(got.TTL, tt.wantTTL) {
+				t.Errorf("Service.Get() TTL = %v, want %v", got.TTL, tt.wantTTL)
+			}
+		})
+	}
+}
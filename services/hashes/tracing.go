@@ -0,0 +1,59 @@
+package hashes
+
+import (
+	"context"
+
+	"github.com/dolefir/refresh-hash/models"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// namespaceKey is the span attribute of the hash namespace.
+const namespaceKey = attribute.Key("hash.namespace")
+
+const tracerName = "github.com/dolefir/refresh-hash/services/hashes"
+
+// startSpan starts a span of the service tracer, the global one by default.
+func (s Service) startSpan(ctx context.Context, name, namespace string) (context.Context, trace.Span) {
+	provider := s.tracerProvider
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+
+	return provider.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(namespaceKey.String(namespace)))
+}
+
+// endSpan ends the span marking it failed on err.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// getHash reads the current hash of the namespace in a repository span.
+func (s Service) getHash(ctx context.Context, namespace string) (hash *models.Hash, err error) {
+	_, span := s.startSpan(ctx, "repository.Get", namespace)
+	defer func() { endSpan(span, err) }()
+
+	return s.hashRepo.Get(namespace)
+}
+
+// setHash stores the hash in a repository span.
+func (s Service) setHash(ctx context.Context, hash *models.Hash) (err error) {
+	_, span := s.startSpan(ctx, "repository.Set", hash.Namespace)
+	defer func() { endSpan(span, err) }()
+
+	return s.hashRepo.Set(hash)
+}
+
+// listHashes reads retained hashes of the namespace in a repository span.
+func (s Service) listHashes(ctx context.Context, namespace string) (history []models.Hash, err error) {
+	_, span := s.startSpan(ctx, "repository.List", namespace)
+	defer func() { endSpan(span, err) }()
+
+	return s.hashRepo.List(namespace)
+}
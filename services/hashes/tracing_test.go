@@ -0,0 +1,66 @@
+package hashes
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/dolefir/refresh-hash/config"
+	"github.com/dolefir/refresh-hash/logger"
+	"github.com/dolefir/refresh-hash/models"
+	"github.com/dolefir/refresh-hash/repository"
+	"github.com/dolefir/refresh-hash/services/mock"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestService_RefreshSpans(t *testing.T) {
+	tests := []struct {
+		name       string
+		hashRepo   repository.Inmem
+		wantSpans  []string
+		wantStatus codes.Code
+	}{
+		{
+			name:       "should trace refresh",
+			hashRepo:   mock.NewInmemMock(),
+			wantSpans:  []string{"repository.Set", "service.Hash.Refresh"},
+			wantStatus: codes.Unset,
+		},
+		{
+			name:       "should mark failed refresh",
+			hashRepo:   mock.NewInmemErrMock(),
+			wantSpans:  []string{"repository.Set", "service.Hash.Refresh"},
+			wantStatus: codes.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.NewConfig("")
+			log := logger.NewLogger((*logger.CFGLogger)(&cfg.Logger), nil)
+			recorder := tracetest.NewSpanRecorder()
+			s := NewService(
+				tt.hashRepo,
+				log,
+				WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))),
+			)
+			_, _ = s.Refresh(context.Background(), models.DefaultNamespace)
+
+			spans := recorder.Ended()
+			var names []string
+			for _, span := range spans {
+				names = append(names, span.Name())
+			}
+			if !reflect.DeepEqual(names, tt.wantSpans) {
+				t.Fatalf("spans = %v, want %v", names, tt.wantSpans)
+			}
+			if spans[0].Parent().SpanID() != spans[1].SpanContext().SpanID() {
+				t.Error("repository span is not a child of the service span")
+			}
+			if got := spans[1].Status().Code; got != tt.wantStatus {
+				t.Errorf("status = %v, want %v", got, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package mock
 
 import (
 	"errors"
+	"time"
 
 	"github.com/dolefir/refresh-hash/models"
 	"github.com/dolefir/refresh-hash/repository"
@@ -15,7 +16,7 @@ func NewInmemMock() *InmemMock {
 	return &InmemMock{}
 }
 
-func (s InmemMock) Get() (*models.Hash, error) {
+func (s InmemMock) Get(namespace string) (*models.Hash, error) {
 	return &models.Hash{ID: "996f2357-31af-4b1a-9889-a075be3de0a9"}, nil
 }
 
@@ -23,6 +24,13 @@ func (s InmemMock) Set(h *models.Hash) error {
 	return nil
 }
 
+func (s InmemMock) List(namespace string) ([]models.Hash, error) {
+	return []models.Hash{
+		{ID: "996f2357-31af-4b1a-9889-a075be3de0a9", Datatime: time.Date(2024, 1, 2, 3, 10, 0, 0, time.UTC)},
+		{ID: "0b9bcb8e-4e3b-4d41-9a3c-1b0f3b9f6f11", Datatime: time.Date(2024, 1, 2, 3, 5, 0, 0, time.UTC)},
+	}, nil
+}
+
 type InmemErrMock struct {
 	repository.Inmem
 }
@@ -31,10 +39,14 @@ func NewInmemErrMock() *InmemErrMock {
 	return &InmemErrMock{}
 }
 
-func (r InmemErrMock) Get() (*models.Hash, error) {
+func (r InmemErrMock) Get(namespace string) (*models.Hash, error) {
 	return nil, errors.New("error")
 }
 
 func (s InmemErrMock) Set(h *models.Hash) error {
 	return errors.New("error")
 }
+
+func (s InmemErrMock) List(namespace string) ([]models.Hash, error) {
+	return nil, errors.New("error")
+}
@@ -2,13 +2,32 @@ package services
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/dolefir/refresh-hash/models"
 )
 
+var (
+	// ErrHashNotFound is returned when no retained hash matches the request.
+	ErrHashNotFound = errors.New("hash not found")
+	// ErrNamespaceNotFound is returned for a namespace not declared in the config.
+	ErrNamespaceNotFound = errors.New("namespace not found")
+)
+
 // Hash is the service interface that
-// describes business logic for working with hash
+// describes business logic for working with hash.
+// Every method addresses a namespace, see models.DefaultNamespace.
 type Hash interface {
-	Get(ctx context.Context) (*models.Hash, error)
-	Refresh(ctx context.Context) error
+	Get(ctx context.Context, namespace string) (*models.Hash, error)
+	// Refresh rotates the hash and returns the new one.
+	Refresh(ctx context.Context, namespace string) (*models.Hash, error)
+	// History returns retained hashes, newest first.
+	History(ctx context.Context, namespace string) ([]models.Hash, error)
+	// ActiveAt returns the hash that was active at the given time.
+	ActiveAt(ctx context.Context, namespace string, at time.Time) (*models.Hash, error)
+	// Validate reports whether the hash is current or in the grace period.
+	Validate(ctx context.Context, namespace, id string) (*models.Validation, error)
+	// Watch streams rotated hashes until ctx is done.
+	Watch(ctx context.Context, namespace string) (<-chan models.Hash, error)
 }
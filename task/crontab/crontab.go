@@ -0,0 +1,13 @@
+// Package crontab holds the cron syntax of ticker schedules,
+// it is apart from task so config can validate expressions
+// without depending on the ticker.
+package crontab
+
+import "github.com/robfig/cron/v3"
+
+// Parser parses ticker.cron and namespace cron expressions, it accepts
+// standard 5 field expressions, an optional leading seconds field
+// and descriptors like @hourly.
+var Parser = cron.NewParser(
+	cron.SecondOptional | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor,
+)
@@ -0,0 +1,51 @@
+package task
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// RetryPolicy defines how a failed refresh is retried
+// before the failure is surfaced.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, 0 or 1 disables retries.
+	MaxAttempts int
+	// InitialInterval is the delay before the first retry.
+	InitialInterval time.Duration
+	// MaxInterval caps the delay between retries.
+	MaxInterval time.Duration
+	// Multiplier grows the delay after each retry, 2 by default.
+	Multiplier float64
+	// Jitter randomizes each delay by +-Jitter fraction, e.g. 0.2.
+	Jitter float64
+}
+
+const defaultMultiplier = 2
+
+func (p RetryPolicy) attempts() int {
+	if p.MaxAttempts < 1 {
+		return 1
+	}
+
+	return p.MaxAttempts
+}
+
+// backoff returns the delay before the retry
+// following the given failed attempt, starting from 1.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier <= 0 {
+		multiplier = defaultMultiplier
+	}
+
+	delay := float64(p.InitialInterval) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxInterval > 0 && delay > float64(p.MaxInterval) {
+		delay = float64(p.MaxInterval)
+	}
+	if p.Jitter > 0 {
+		delay += delay * p.Jitter * (2*rand.Float64() - 1)
+	}
+
+	return time.Duration(delay)
+}
@@ -0,0 +1,40 @@
+package task
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetryPolicy_backoff(t *testing.T) {
+	policy := RetryPolicy{
+		MaxAttempts:     5,
+		InitialInterval: time.Second,
+		MaxInterval:     5 * time.Second,
+	}
+	tests := []struct {
+		name    string
+		attempt int
+		want    time.Duration
+	}{
+		{name: "should returns initial interval", attempt: 1, want: time.Second},
+		{name: "should grow exponentially", attempt: 3, want: 4 * time.Second},
+		{name: "should be capped by max interval", attempt: 4, want: 5 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.backoff(tt.attempt); got != tt.want {
+				t.Errorf("RetryPolicy.backoff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_backoffJitter(t *testing.T) {
+	policy := RetryPolicy{InitialInterval: time.Second, Jitter: 0.2}
+	for i := 0; i < 100; i++ {
+		got := policy.backoff(1)
+		if got < 800*time.Millisecond || got > 1200*time.Millisecond {
+			t.Fatalf("RetryPolicy.backoff() = %v, want within 20%% of 1s", got)
+		}
+	}
+}
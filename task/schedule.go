@@ -0,0 +1,63 @@
+package task
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dolefir/refresh-hash/task/crontab"
+	"github.com/robfig/cron/v3"
+)
+
+// Schedule describes when the hash is rotated.
+type Schedule interface {
+	// Next returns the next rotation time after t.
+	Next(t time.Time) time.Time
+}
+
+// Every is a fixed interval schedule.
+type Every time.Duration
+
+// Next returns the next rotation time after t.
+func (e Every) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(e))
+}
+
+// cronSchedule is a wall-clock schedule in a time zone.
+type cronSchedule struct {
+	schedule cron.Schedule
+	location *time.Location
+}
+
+// Next returns the next rotation time after t.
+func (c cronSchedule) Next(t time.Time) time.Time {
+	return c.schedule.Next(t.In(c.location))
+}
+
+// ParseSchedule returns the cron schedule when the expression
+// is set and the fixed interval schedule otherwise.
+// An empty time zone is UTC.
+func ParseSchedule(timer time.Duration, expr, timeZone string) (Schedule, error) {
+	if expr == "" {
+		if timer <= 0 {
+			return nil, errors.New("task: timer must be positive")
+		}
+		return Every(timer), nil
+	}
+
+	location := time.UTC
+	if timeZone != "" {
+		loc, err := time.LoadLocation(timeZone)
+		if err != nil {
+			return nil, fmt.Errorf("task: time zone %q: %w", timeZone, err)
+		}
+		location = loc
+	}
+
+	schedule, err := crontab.Parser.Parse(expr)
+	if err != nil {
+		return nil, fmt.Errorf("task: cron %q: %w", expr, err)
+	}
+
+	return cronSchedule{schedule: schedule, location: location}, nil
+}
@@ -0,0 +1,76 @@
+package task
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSchedule(t *testing.T) {
+	from := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	type args struct {
+		timer    time.Duration
+		expr     string
+		timeZone string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    time.Time
+		wantErr bool
+	}{
+		{
+			name: "should returns fixed interval",
+			args: args{timer: 5 * time.Minute},
+			want: from.Add(5 * time.Minute),
+		},
+		{
+			name: "should returns daily schedule",
+			args: args{timer: 5 * time.Minute, expr: "0 0 * * *"},
+			want: time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "should returns schedule with seconds",
+			args: args{expr: "30 * * * * *"},
+			want: time.Date(2024, 1, 2, 3, 4, 30, 0, time.UTC),
+		},
+		{
+			name: "should returns descriptor schedule",
+			args: args{expr: "@hourly"},
+			want: time.Date(2024, 1, 2, 4, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "should returns schedule in time zone",
+			args: args{expr: "0 0 * * *", timeZone: "America/New_York"},
+			want: time.Date(2024, 1, 2, 5, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "should returns error for invalid expression",
+			args:    args{expr: "61 * * * *"},
+			wantErr: true,
+		},
+		{
+			name:    "should returns error for unknown time zone",
+			args:    args{expr: "@daily", timeZone: "Mars/Olympus"},
+			wantErr: true,
+		},
+		{
+			name:    "should returns error for non-positive timer",
+			args:    args{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSchedule(tt.args.timer, tt.args.expr, tt.args.timeZone)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSchedule() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if next := got.Next(from); !next.Equal(tt.want) {
+				t.Errorf("Schedule.Next() = %v, want %v", next, tt.want)
+			}
+		})
+	}
+}
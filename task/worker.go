@@ -2,26 +2,124 @@ package task
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/dolefir/refresh-hash/clock"
 	"github.com/dolefir/refresh-hash/logger"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
+var tracer = otel.Tracer("github.com/dolefir/refresh-hash/task")
+
 type Refresher interface {
 	Refresh(ctx context.Context) error
 }
 
+// RefresherFunc adapts a function to the Refresher interface.
+type RefresherFunc func(ctx context.Context) error
+
+// Refresh calls f(ctx).
+func (f RefresherFunc) Refresh(ctx context.Context) error {
+	return f(ctx)
+}
+
 type refreshTicker struct {
-	timer        time.Duration
+	// mu guards schedule replaced by Reschedule.
+	mu           sync.Mutex
+	schedule     Schedule
+	rescheduled  chan struct{}
 	queryTimeout time.Duration
 	refresher    Refresher
 	log          logger.Logger
+	retry        RetryPolicy
+	ready        atomic.Bool
+	clock        clock.Clock
+	// next is the next scheduled run in unix nanoseconds, 0 until started.
+	next atomic.Int64
+	// last is the last successful rotation in unix nanoseconds,
+	// the start time until the first rotation, 0 until started.
+	last atomic.Int64
 }
 
 // RefreshTicker is the service interface that
 // describes business logic for working with ticker.
 type RefreshTicker interface {
 	Start(ctx context.Context) error
+	// Ready reports whether the last rotation succeeded,
+	// it turns false once all retries of a refresh failed.
+	Ready() bool
+	// Next returns the next scheduled rotation,
+	// false if the ticker is not started.
+	Next() (time.Time, bool)
+	// Check returns an error if the ticker is not started, its last
+	// refresh failed or it has not rotated for multiplier intervals.
+	Check(now time.Time, multiplier float64) error
+	// Reschedule replaces the schedule of a running ticker,
+	// the next rotation is counted from the last one.
+	Reschedule(schedule Schedule)
+}
+
+// Tickers is a set of tickers by namespace.
+type Tickers map[string]RefreshTicker
+
+// NextRotation returns the next scheduled rotation of the namespace.
+func (t Tickers) NextRotation(namespace string) (time.Time, bool) {
+	ticker, ok := t[namespace]
+	if !ok {
+		return time.Time{}, false
+	}
+
+	return ticker.Next()
+}
+
+// Check returns errors of all unhealthy tickers.
+func (t Tickers) Check(now time.Time, multiplier float64) error {
+	names := make([]string, 0, len(t))
+	for name := range t {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []error
+	for _, name := range names {
+		if err := t[name].Check(now, multiplier); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Option options for ticker setup.
+type Option func(r *refreshTicker)
+
+// WithRetry retries a failed refresh according to the policy.
+func WithRetry(policy RetryPolicy) Option {
+	return func(r *refreshTicker) {
+		r.retry = policy
+	}
+}
+
+// WithSchedule rotates the hash by the schedule instead of the fixed timer.
+func WithSchedule(schedule Schedule) Option {
+	return func(r *refreshTicker) {
+		r.schedule = schedule
+	}
+}
+
+// WithClock sets the clock, the real one by default.
+func WithClock(c clock.Clock) Option {
+	return func(r *refreshTicker) {
+		r.clock = c
+	}
 }
 
 // NewRefreshTicker returns a new Ticker for refresh hash.
@@ -30,43 +128,199 @@ func NewRefreshTicker(
 	queryTimeout time.Duration,
 	refresher Refresher,
 	log logger.Logger,
+	options ...Option,
 ) RefreshTicker {
-	return &refreshTicker{
-		timer:        timer,
+	r := &refreshTicker{
+		schedule:     Every(timer),
+		rescheduled:  make(chan struct{}, 1),
 		queryTimeout: queryTimeout,
 		refresher:    refresher,
-		log:          log,
+		log:          log.With(logger.ComponentField, "ticker"),
+		clock:        clock.New(),
+	}
+	r.ready.Store(true)
+	for _, option := range options {
+		option(r)
+	}
+
+	return r
+}
+
+// Ready reports whether the last rotation succeeded.
+func (r *refreshTicker) Ready() bool {
+	return r.ready.Load()
+}
+
+// Check returns an error if the ticker is not started, its last
+// refresh failed or it has not rotated for multiplier intervals.
+func (r *refreshTicker) Check(now time.Time, multiplier float64) error {
+	last := r.last.Load()
+	if last == 0 {
+		return errors.New("ticker is not running")
+	}
+	if !r.Ready() {
+		return errors.New("last refresh failed")
+	}
+
+	lastRotation := time.Unix(0, last)
+	interval := r.getSchedule().Next(lastRotation).Sub(lastRotation)
+	if age := now.Sub(lastRotation); age > time.Duration(multiplier*float64(interval)) {
+		return fmt.Errorf("last rotation %s ago, expected every %s", age.Round(time.Second), interval)
+	}
+
+	return nil
+}
+
+// Reschedule replaces the schedule of a running ticker.
+func (r *refreshTicker) Reschedule(schedule Schedule) {
+	r.mu.Lock()
+	r.schedule = schedule
+	r.mu.Unlock()
+
+	select {
+	case r.rescheduled <- struct{}{}:
+	default:
+	}
+}
+
+func (r *refreshTicker) getSchedule() Schedule {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.schedule
+}
+
+// Next returns the next scheduled rotation.
+func (r *refreshTicker) Next() (time.Time, bool) {
+	next := r.next.Load()
+	if next == 0 {
+		return time.Time{}, false
 	}
+
+	return time.Unix(0, next), true
 }
 
+// refresh runs a single attempt, it is not cancelled with ctx
+// so a rotation in flight on shutdown is completed.
 func (r *refreshTicker) refresh(ctx context.Context) error {
-	timeOut, cancel := context.WithTimeout(ctx, r.queryTimeout)
+	timeOut, cancel := context.WithTimeout(detached{ctx}, r.queryTimeout)
 	defer cancel()
 	err := r.refresher.Refresh(timeOut)
 	if err != nil {
-		r.log.Errorf("task.Refresh: %s", err)
+		r.log.ErrorfCtx(ctx, "refresh hash: %s", err)
 		return err
 	}
 
 	return nil
 }
 
+// refreshWithRetry retries a failed refresh with exponential
+// backoff, it returns the last error once all attempts failed.
+func (r *refreshTicker) refreshWithRetry(ctx context.Context) error {
+	attempts := r.retry.attempts()
+	for attempt := 1; ; attempt++ {
+		err := r.refresh(ctx)
+		if err == nil || attempt >= attempts {
+			return err
+		}
+
+		delay := r.retry.backoff(attempt)
+		r.log.WarnfCtx(ctx, "refresh attempt %d/%d failed, retry in %s", attempt, attempts, delay)
+		trace.SpanFromContext(ctx).AddEvent("retry", trace.WithAttributes(
+			attribute.Int("attempt", attempt),
+			attribute.String("delay", delay.String()),
+		))
+
+		timer := r.clock.NewTimer(delay)
+		select {
+		case <-timer.C():
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// run is a rotation with retries traced as a root span.
+func (r *refreshTicker) run(ctx context.Context) (err error) {
+	ctx, span := tracer.Start(ctx, "task.Refresh", trace.WithNewRoot())
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
+	return r.refreshWithRetry(ctx)
+}
+
 // Start timer to rework the hash.
+// A refresh failed after all retries flips Ready to false,
+// rotation keeps going on the next tick.
 func (r *refreshTicker) Start(ctx context.Context) error {
-	ticker := time.NewTicker(r.timer)
-	defer ticker.Stop()
+	now := r.clock.Now()
+	next := r.getSchedule().Next(now)
+	r.next.Store(next.UnixNano())
+	defer r.next.Store(0)
+	r.last.Store(now.UnixNano())
+	defer r.last.Store(0)
+	timer := r.clock.NewTimer(next.Sub(now))
+	defer timer.Stop()
 	for {
 		select {
-		case <-ticker.C:
+		case <-timer.C():
+			// The next run is counted from the scheduled time,
+			// a run longer than the interval skips missed ones.
+			now := r.clock.Now()
+			schedule := r.getSchedule()
+			if next = schedule.Next(next); next.Before(now) {
+				next = schedule.Next(now)
+			}
+			r.next.Store(next.UnixNano())
+			timer.Reset(next.Sub(now))
+
 			r.log.Debugf("start ticker")
-			if err := r.refresh(ctx); err != nil {
-				r.log.Errorf("error refresh %v", err)
-				return err
+			if err := r.run(ctx); err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+				r.ready.Store(false)
+				r.log.Errorf("error refresh, retries exhausted: %v", err)
+				continue
 			}
+			r.ready.Store(true)
+			r.last.Store(r.clock.Now().UnixNano())
 			r.log.Debugf("done")
 
+		case <-r.rescheduled:
+			// A rotation overdue by the new schedule runs at once.
+			now := r.clock.Now()
+			if next = r.getSchedule().Next(time.Unix(0, r.last.Load())); next.Before(now) {
+				next = now
+			}
+			r.next.Store(next.UnixNano())
+			if !timer.Stop() {
+				select {
+				case <-timer.C():
+				default:
+				}
+			}
+			timer.Reset(next.Sub(now))
+			r.log.Infof("rescheduled, next rotation at %s", next.Format(time.RFC3339))
+
 		case <-ctx.Done():
 			return nil
 		}
 	}
 }
+
+// detached keeps values of the parent context but not its cancellation.
+type detached struct {
+	parent context.Context
+}
+
+func (detached) Deadline() (time.Time, bool)         { return time.Time{}, false }
+func (detached) Done() <-chan struct{}               { return nil }
+func (detached) Err() error                          { return nil }
+func (d detached) Value(key interface{}) interface{} { return d.parent.Value(key) }
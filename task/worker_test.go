@@ -2,9 +2,12 @@ package task
 
 import (
 	"context"
+	"errors"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/dolefir/refresh-hash/clock"
 	"github.com/dolefir/refresh-hash/config"
 	"github.com/dolefir/refresh-hash/logger"
 )
@@ -22,8 +25,10 @@ func Test_refreshTicker_StartAndCancel(t *testing.T) {
 	)
 
 	counter := 0
+	calls := make(chan struct{})
 	refresherMock := RefresherMock(func(ctx context.Context) error {
 		counter++
+		calls <- struct{}{}
 		return nil
 	})
 
@@ -33,15 +38,29 @@ func Test_refreshTicker_StartAndCancel(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	ticker := NewRefreshTicker(tickerDuration, time.Second, refresherMock, log)
+	clk := clock.NewFake(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+	ticker := NewRefreshTicker(tickerDuration, time.Second, refresherMock, log, WithClock(clk))
 
+	done := make(chan error)
 	go func() {
-		const timeBuffer = time.Millisecond
-		time.Sleep(tickerDuration*2 + timeBuffer)
-		cancel()
+		done <- ticker.Start(ctx)
 	}()
 
-	if err := ticker.Start(ctx); err != nil {
+	clk.BlockUntil(1)
+	if next, ok := ticker.Next(); !ok || !next.Equal(clk.Now().Add(tickerDuration)) {
+		t.Errorf("wrong next run (%v, %v), expected - %v", next, ok, clk.Now().Add(tickerDuration))
+	}
+
+	for i := 0; i < expectedCallsCount; i++ {
+		clk.BlockUntil(1)
+		clk.Advance(tickerDuration)
+		<-calls
+	}
+	// Half of the interval must not trigger one more call.
+	clk.Advance(tickerDuration / 2)
+	cancel()
+
+	if err := <-done; err != nil {
 		t.Fatal(err)
 	}
 
@@ -49,3 +68,181 @@ func Test_refreshTicker_StartAndCancel(t *testing.T) {
 		t.Errorf("wrong number of calls (%d), expected - %d", counter, expectedCallsCount)
 	}
 }
+
+func Test_refreshTicker_refreshWithRetry(t *testing.T) {
+	tests := []struct {
+		name      string
+		failures  int
+		wantCalls int
+		wantOK    bool
+	}{
+		{
+			name:      "should recover after retries",
+			failures:  2,
+			wantCalls: 3,
+			wantOK:    true,
+		},
+		{
+			name:      "should returns error after exhausting retries",
+			failures:  5,
+			wantCalls: 3,
+			wantOK:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var counter atomic.Int32
+			refresherMock := RefresherMock(func(ctx context.Context) error {
+				if int(counter.Add(1)) <= tt.failures {
+					return errors.New("error")
+				}
+				return nil
+			})
+
+			cfg := config.NewConfig("")
+			log := logger.NewLogger((*logger.CFGLogger)(&cfg.Logger), nil)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			ticker := NewRefreshTicker(time.Hour, time.Second, refresherMock, log,
+				WithRetry(RetryPolicy{MaxAttempts: 3, InitialInterval: time.Millisecond}))
+			rt := ticker.(*refreshTicker)
+
+			err := rt.refreshWithRetry(ctx)
+			if (err == nil) != tt.wantOK {
+				t.Errorf("refreshWithRetry() error = %v, wantOK %v", err, tt.wantOK)
+			}
+			if got := int(counter.Load()); got != tt.wantCalls {
+				t.Errorf("wrong number of calls (%d), expected - %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func Test_refreshTicker_KeepsRunningAfterFailure(t *testing.T) {
+	const (
+		tickerDuration     = time.Millisecond * 5
+		expectedCallsCount = 3
+	)
+
+	counter := 0
+	calls := make(chan struct{})
+	refresherMock := RefresherMock(func(ctx context.Context) error {
+		counter++
+		calls <- struct{}{}
+		return errors.New("error")
+	})
+
+	cfg := config.NewConfig("")
+	log := logger.NewLogger((*logger.CFGLogger)(&cfg.Logger), nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clk := clock.NewFake(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+	ticker := NewRefreshTicker(tickerDuration, time.Second, refresherMock, log, WithClock(clk))
+
+	done := make(chan error)
+	go func() {
+		done <- ticker.Start(ctx)
+	}()
+
+	for i := 0; i < expectedCallsCount; i++ {
+		clk.BlockUntil(1)
+		clk.Advance(tickerDuration)
+		<-calls
+	}
+	clk.BlockUntil(1)
+	ready := ticker.Ready()
+	cancel()
+
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if counter != expectedCallsCount {
+		t.Errorf("ticker stopped after a failure, calls (%d), expected - %d", counter, expectedCallsCount)
+	}
+	if ready {
+		t.Error("ticker is ready after a failed refresh")
+	}
+}
+
+func Test_refreshTicker_Check(t *testing.T) {
+	const tickerDuration = time.Minute
+
+	cfg := config.NewConfig("")
+	log := logger.NewLogger((*logger.CFGLogger)(&cfg.Logger), nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	calls := make(chan struct{})
+	clk := clock.NewFake(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+	ticker := NewRefreshTicker(tickerDuration, time.Second, RefresherMock(func(ctx context.Context) error {
+		calls <- struct{}{}
+		return nil
+	}), log, WithClock(clk))
+
+	if err := ticker.Check(clk.Now(), 2); err == nil {
+		t.Error("expected error of not started ticker")
+	}
+
+	done := make(chan error)
+	go func() {
+		done <- ticker.Start(ctx)
+	}()
+
+	clk.BlockUntil(1)
+	clk.Advance(tickerDuration)
+	<-calls
+	clk.BlockUntil(1)
+
+	if err := ticker.Check(clk.Now().Add(tickerDuration), 2); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := ticker.Check(clk.Now().Add(3*tickerDuration), 2); err == nil {
+		t.Error("expected error of stale rotation")
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_refreshTicker_Reschedule(t *testing.T) {
+	cfg := config.NewConfig("")
+	log := logger.NewLogger((*logger.CFGLogger)(&cfg.Logger), nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	calls := make(chan struct{})
+	clk := clock.NewFake(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+	ticker := NewRefreshTicker(10*time.Minute, time.Second, RefresherMock(func(ctx context.Context) error {
+		calls <- struct{}{}
+		return nil
+	}), log, WithClock(clk))
+
+	done := make(chan error)
+	go func() {
+		done <- ticker.Start(ctx)
+	}()
+
+	clk.BlockUntil(1)
+	clk.Advance(2 * time.Minute)
+	// The last rotation is older than the new interval, so it runs at once.
+	ticker.Reschedule(Every(time.Minute))
+	<-calls
+
+	clk.BlockUntil(1)
+	if next, ok := ticker.Next(); !ok || !next.Equal(clk.Now().Add(time.Minute)) {
+		t.Errorf("wrong next run (%v, %v), expected - %v", next, ok, clk.Now().Add(time.Minute))
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}